@@ -133,7 +133,11 @@ func (r *Receipt) UnmarshalJSON(input []byte) error {
 	if dec.Bloom != nil {
 		r.Bloom = *dec.Bloom
 	}
+	// Some nodes return null rather than [] for receipts without logs
 	r.Logs = dec.Logs
+	if r.Logs == nil {
+		r.Logs = []*Log{}
+	}
 	if dec.TxHash != nil {
 		r.TxHash = *dec.TxHash
 	}
@@ -192,6 +196,22 @@ func FromGethLog(gl *gethTypes.Log) *Log {
 	}
 }
 
+// ToGethLog converts the Log to a gethTypes.Log, so that it can be passed to
+// parsers and generated contract bindings
+func (l Log) ToGethLog() gethTypes.Log {
+	return gethTypes.Log{
+		Address:     l.Address,
+		Topics:      l.Topics,
+		Data:        l.Data,
+		BlockNumber: l.BlockNumber,
+		TxHash:      l.TxHash,
+		TxIndex:     l.TxIndex,
+		BlockHash:   l.BlockHash,
+		Index:       l.Index,
+		Removed:     l.Removed,
+	}
+}
+
 // MarshalJSON marshals as JSON.
 func (l Log) MarshalJSON() ([]byte, error) {
 	type Log struct {
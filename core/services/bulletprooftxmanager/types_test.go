@@ -0,0 +1,61 @@
+package bulletprooftxmanager_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/services/bulletprooftxmanager"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/libocr/gethwrappers/offchainaggregator"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceipt_UnmarshalJSON_NullLogs(t *testing.T) {
+	var receipt bulletprooftxmanager.Receipt
+	err := json.Unmarshal([]byte(`{"transactionHash": "0x882969652440ccf14a5dbb9bd53eb21cb1e11e5c07736a935cba057e66b2b3bf", "logs": null}`), &receipt)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToHash("0x882969652440ccf14a5dbb9bd53eb21cb1e11e5c07736a935cba057e66b2b3bf"), receipt.TxHash)
+	require.NotNil(t, receipt.Logs)
+	require.Len(t, receipt.Logs, 0)
+}
+
+func TestLog_ToGethLog(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(offchainaggregator.OffchainAggregatorABI))
+	require.NoError(t, err)
+	signer := cltest.NewAddress()
+	data, err := contractABI.Events["ConfigSet"].Inputs.NonIndexed().Pack(
+		uint32(0),
+		uint64(3),
+		[]common.Address{signer},
+		[]common.Address{cltest.NewAddress()},
+		uint8(1),
+		uint64(1),
+		[]byte{1, 2, 3},
+	)
+	require.NoError(t, err)
+
+	log := bulletprooftxmanager.Log{
+		Address:     cltest.NewAddress(),
+		Topics:      []common.Hash{offchainreporting.OCRContractConfigSet},
+		Data:        models.UntrustedBytes(data),
+		BlockNumber: 42,
+		TxHash:      cltest.NewHash(),
+		TxIndex:     1,
+		BlockHash:   cltest.NewHash(),
+		Index:       2,
+	}
+	gethLog := log.ToGethLog()
+	require.Equal(t, []byte(data), gethLog.Data)
+	require.Equal(t, &log, bulletprooftxmanager.FromGethLog(&gethLog))
+
+	cc, err := offchainreporting.ParseOCRConfigSet(gethLog)
+	require.NoError(t, err)
+	require.Equal(t, []common.Address{signer}, cc.Signers)
+	require.Equal(t, []byte{1, 2, 3}, cc.Encoded)
+}
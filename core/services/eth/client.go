@@ -300,10 +300,31 @@ func (client *client) HeaderByNumber(ctx context.Context, number *big.Int) (*mod
 	return head, err
 }
 
+// Block tags not yet known to the vendored go-ethereum, with the values later
+// versions give them. Pass e.g. big.NewInt(int64(eth.FinalizedBlockNumber))
+// to HeaderByNumber to get the finalized head.
+const (
+	FinalizedBlockNumber = rpc.BlockNumber(-3)
+	SafeBlockNumber      = rpc.BlockNumber(-4)
+)
+
 func toBlockNumArg(number *big.Int) string {
 	if number == nil {
 		return "latest"
 	}
+	if number.Sign() >= 0 || !number.IsInt64() {
+		return hexutil.EncodeBig(number)
+	}
+	switch rpc.BlockNumber(number.Int64()) {
+	case rpc.LatestBlockNumber:
+		return "latest"
+	case rpc.PendingBlockNumber:
+		return "pending"
+	case FinalizedBlockNumber:
+		return "finalized"
+	case SafeBlockNumber:
+		return "safe"
+	}
 	return hexutil.EncodeBig(number)
 }
 
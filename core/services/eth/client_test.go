@@ -21,6 +21,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -295,6 +296,40 @@ func TestEthClient_HeaderByNumber(t *testing.T) {
 	}
 }
 
+func TestEthClient_HeaderByNumber_BlockTags(t *testing.T) {
+	resp := `{"jsonrpc":"2.0","id":1,"result":{"hash":"0x41800b5c3f1717687d85fc9018faac0a6e90b39deaa0b99e7fe4fe796ddeb26a","number":"0x1","parentHash":"0x41941023680923e0fe4d74a34bdac8141f2540e3ae90623718e47d66d1ca4a2d","timestamp":"0x58318da2"}}`
+	tests := []struct {
+		number *big.Int
+		tag    string
+	}{
+		{nil, "latest"},
+		{big.NewInt(int64(rpc.LatestBlockNumber)), "latest"},
+		{big.NewInt(int64(rpc.PendingBlockNumber)), "pending"},
+		{big.NewInt(int64(eth.FinalizedBlockNumber)), "finalized"},
+		{big.NewInt(int64(eth.SafeBlockNumber)), "safe"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.tag, func(t *testing.T) {
+			_, url, cleanup := cltest.NewWSServer(resp, func(data []byte) {
+				req := cltest.ParseJSON(t, bytes.NewReader(data))
+				require.Equal(t, test.tag, req.Get("params").Get("0").String())
+			})
+			defer cleanup()
+
+			ethClient, err := eth.NewClient(url)
+			require.NoError(t, err)
+			err = ethClient.Dial(context.Background())
+			require.NoError(t, err)
+			defer ethClient.Close()
+
+			_, err = ethClient.HeaderByNumber(context.Background(), test.number)
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestEthClient_SendTransaction_NoSecondaryURL(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,398 @@
+// Package testutil provides eth.Client implementations that record the calls
+// made to a node into a cassette file and serve them back, for deterministic
+// tests.
+//
+// It is test-only and must not be used outside of tests.
+package testutil
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	gethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/assets"
+	"github.com/smartcontractkit/chainlink/core/services/eth"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+)
+
+// ErrNotInCassette is returned by a ReplayClient for a call that is not in
+// its cassette, including calls to methods that are never recorded
+var ErrNotInCassette = errors.New("not in cassette")
+
+type (
+	// rpcInteraction is a call recorded in a cassette along with its response
+	rpcInteraction struct {
+		Method string          `json:"method"`
+		Args   json.RawMessage `json:"args"`
+		Result json.RawMessage `json:"result,omitempty"`
+		Error  string          `json:"error,omitempty"`
+	}
+
+	// batchElemCall and batchElemResult are the recorded arguments and
+	// results of one element of a batch call
+	batchElemCall struct {
+		Method string        `json:"method"`
+		Args   []interface{} `json:"args"`
+	}
+	batchElemResult struct {
+		Result json.RawMessage `json:"result,omitempty"`
+		Error  string          `json:"error,omitempty"`
+	}
+
+	// RecordingClient is an eth.Client that records the calls made through
+	// it, and their responses, so that they can be saved to a cassette file
+	// and served back by a ReplayClient. Only CallContract, CodeAt,
+	// FilterLogs, HeaderByNumber, TransactionReceipt and BatchCallContext
+	// are recorded, other calls are passed through unrecorded.
+	RecordingClient struct {
+		eth.Client
+		interactions []rpcInteraction
+		mu           sync.Mutex
+	}
+
+	// ReplayClient is an eth.Client that serves the responses recorded in a
+	// cassette file instead of calling a node. Calls are matched by method
+	// and arguments; repeated calls are served the recorded responses in
+	// order, the last being served again once the others are used up. Calls
+	// that are not in the cassette return ErrNotInCassette.
+	ReplayClient struct {
+		responses map[string][]rpcInteraction
+		mu        sync.Mutex
+	}
+)
+
+var (
+	_ eth.Client = &RecordingClient{}
+	_ eth.Client = &ReplayClient{}
+)
+
+// NewRecordingClient returns a RecordingClient that makes its calls through
+// client
+func NewRecordingClient(client eth.Client) *RecordingClient {
+	return &RecordingClient{Client: client}
+}
+
+// Save writes the calls recorded so far to a cassette file at path
+func (c *RecordingClient) Save(path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not encode cassette")
+	}
+	return errors.Wrapf(ioutil.WriteFile(path, b, 0600), "could not write cassette to %s", path)
+}
+
+// record adds the call to the cassette, returning an error if it could not
+// be encoded
+func (c *RecordingClient) record(method string, args interface{}, result interface{}, err error) error {
+	interaction := rpcInteraction{Method: method}
+	var marshalErr error
+	if interaction.Args, marshalErr = json.Marshal(args); marshalErr != nil {
+		return errors.Wrapf(marshalErr, "could not encode arguments of %s", method)
+	}
+	if err != nil {
+		interaction.Error = err.Error()
+	} else if interaction.Result, marshalErr = json.Marshal(result); marshalErr != nil {
+		return errors.Wrapf(marshalErr, "could not encode result of %s", method)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions = append(c.interactions, interaction)
+	return nil
+}
+
+// CallContract complies with eth.Client interface
+func (c *RecordingClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	out, err := c.Client.CallContract(ctx, msg, blockNumber)
+	if recordErr := c.record("CallContract", []interface{}{msg, blockNumber}, hexutil.Bytes(out), err); recordErr != nil {
+		return nil, recordErr
+	}
+	return out, err
+}
+
+// CodeAt complies with eth.Client interface
+func (c *RecordingClient) CodeAt(ctx context.Context, account gethCommon.Address, blockNumber *big.Int) ([]byte, error) {
+	code, err := c.Client.CodeAt(ctx, account, blockNumber)
+	if recordErr := c.record("CodeAt", []interface{}{account, blockNumber}, hexutil.Bytes(code), err); recordErr != nil {
+		return nil, recordErr
+	}
+	return code, err
+}
+
+// FilterLogs complies with eth.Client interface
+func (c *RecordingClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	logs, err := c.Client.FilterLogs(ctx, q)
+	if recordErr := c.record("FilterLogs", []interface{}{q}, logs, err); recordErr != nil {
+		return nil, recordErr
+	}
+	return logs, err
+}
+
+// HeaderByNumber complies with eth.Client interface
+func (c *RecordingClient) HeaderByNumber(ctx context.Context, n *big.Int) (*models.Head, error) {
+	head, err := c.Client.HeaderByNumber(ctx, n)
+	if recordErr := c.record("HeaderByNumber", []interface{}{n}, head, err); recordErr != nil {
+		return nil, recordErr
+	}
+	return head, err
+}
+
+// TransactionReceipt complies with eth.Client interface
+func (c *RecordingClient) TransactionReceipt(ctx context.Context, txHash gethCommon.Hash) (*types.Receipt, error) {
+	receipt, err := c.Client.TransactionReceipt(ctx, txHash)
+	if recordErr := c.record("TransactionReceipt", []interface{}{txHash}, receipt, err); recordErr != nil {
+		return nil, recordErr
+	}
+	return receipt, err
+}
+
+// BatchCallContext complies with eth.Client interface
+func (c *RecordingClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	err := c.Client.BatchCallContext(ctx, b)
+	results := make([]batchElemResult, len(b))
+	if err == nil {
+		for i, elem := range b {
+			if elem.Error != nil {
+				results[i].Error = elem.Error.Error()
+				continue
+			}
+			result, marshalErr := json.Marshal(elem.Result)
+			if marshalErr != nil {
+				return errors.Wrapf(marshalErr, "could not encode result of batched %s", elem.Method)
+			}
+			results[i].Result = result
+		}
+	}
+	if recordErr := c.record("BatchCallContext", batchCalls(b), results, err); recordErr != nil {
+		return recordErr
+	}
+	return err
+}
+
+// NewReplayClient returns a ReplayClient serving the responses in the
+// cassette file at path
+func NewReplayClient(path string) (*ReplayClient, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read cassette from %s", path)
+	}
+	var interactions []rpcInteraction
+	if err = json.Unmarshal(b, &interactions); err != nil {
+		return nil, errors.Wrapf(err, "could not decode cassette from %s", path)
+	}
+	c := &ReplayClient{responses: make(map[string][]rpcInteraction)}
+	for _, interaction := range interactions {
+		key := interaction.Method + string(interaction.Args)
+		c.responses[key] = append(c.responses[key], interaction)
+	}
+	return c, nil
+}
+
+// replay decodes the recorded response to the call into result, or returns
+// the recorded error
+func (c *ReplayClient) replay(method string, args interface{}, result interface{}) error {
+	b, err := json.Marshal(args)
+	if err != nil {
+		return errors.Wrapf(err, "could not encode arguments of %s", method)
+	}
+	key := method + string(b)
+	c.mu.Lock()
+	recorded := c.responses[key]
+	if len(recorded) == 0 {
+		c.mu.Unlock()
+		return errors.Wrapf(ErrNotInCassette, "%s with arguments %s", method, b)
+	}
+	interaction := recorded[0]
+	if len(recorded) > 1 {
+		c.responses[key] = recorded[1:]
+	}
+	c.mu.Unlock()
+
+	if interaction.Error != "" {
+		return errors.New(interaction.Error)
+	}
+	return errors.Wrapf(json.Unmarshal(interaction.Result, result), "could not decode recorded result of %s", method)
+}
+
+// notRecorded is returned by the methods a RecordingClient never records
+func notRecorded(method string) error {
+	return errors.Wrapf(ErrNotInCassette, "%s is never recorded", method)
+}
+
+//
+// Recorded methods
+//
+
+// CallContract complies with eth.Client interface
+func (c *ReplayClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var out hexutil.Bytes
+	if err := c.replay("CallContract", []interface{}{msg, blockNumber}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CodeAt complies with eth.Client interface
+func (c *ReplayClient) CodeAt(ctx context.Context, account gethCommon.Address, blockNumber *big.Int) ([]byte, error) {
+	var code hexutil.Bytes
+	if err := c.replay("CodeAt", []interface{}{account, blockNumber}, &code); err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+// FilterLogs complies with eth.Client interface
+func (c *ReplayClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	if err := c.replay("FilterLogs", []interface{}{q}, &logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// HeaderByNumber complies with eth.Client interface
+func (c *ReplayClient) HeaderByNumber(ctx context.Context, n *big.Int) (*models.Head, error) {
+	var head *models.Head
+	if err := c.replay("HeaderByNumber", []interface{}{n}, &head); err != nil {
+		return nil, err
+	}
+	return head, nil
+}
+
+// TransactionReceipt complies with eth.Client interface
+func (c *ReplayClient) TransactionReceipt(ctx context.Context, txHash gethCommon.Hash) (*types.Receipt, error) {
+	var receipt *types.Receipt
+	if err := c.replay("TransactionReceipt", []interface{}{txHash}, &receipt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// BatchCallContext complies with eth.Client interface
+func (c *ReplayClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	var results []batchElemResult
+	if err := c.replay("BatchCallContext", batchCalls(b), &results); err != nil {
+		return err
+	}
+	if len(results) != len(b) {
+		return errors.Errorf("recorded batch has %d results, expected %d", len(results), len(b))
+	}
+	for i, result := range results {
+		if result.Error != "" {
+			b[i].Error = errors.New(result.Error)
+			continue
+		}
+		if err := json.Unmarshal(result.Result, b[i].Result); err != nil {
+			b[i].Error = errors.Wrapf(err, "could not decode recorded result of batched %s", b[i].Method)
+		}
+	}
+	return nil
+}
+
+//
+// Methods that are never recorded
+//
+
+// Dial complies with eth.Client interface, there is no node to dial
+func (c *ReplayClient) Dial(ctx context.Context) error { return nil }
+
+// Close complies with eth.Client interface
+func (c *ReplayClient) Close() {}
+
+// GetERC20Balance complies with eth.Client interface
+func (c *ReplayClient) GetERC20Balance(address gethCommon.Address, contractAddress gethCommon.Address) (*big.Int, error) {
+	return nil, notRecorded("GetERC20Balance")
+}
+
+// GetLINKBalance complies with eth.Client interface
+func (c *ReplayClient) GetLINKBalance(linkAddress gethCommon.Address, address gethCommon.Address) (*assets.Link, error) {
+	return nil, notRecorded("GetLINKBalance")
+}
+
+// GetEthBalance complies with eth.Client interface
+func (c *ReplayClient) GetEthBalance(ctx context.Context, account gethCommon.Address, blockNumber *big.Int) (*assets.Eth, error) {
+	return nil, notRecorded("GetEthBalance")
+}
+
+// SendRawTx complies with eth.Client interface
+func (c *ReplayClient) SendRawTx(bytes []byte) (gethCommon.Hash, error) {
+	return gethCommon.Hash{}, notRecorded("SendRawTx")
+}
+
+// Call complies with eth.Client interface
+func (c *ReplayClient) Call(result interface{}, method string, args ...interface{}) error {
+	return notRecorded("Call")
+}
+
+// CallContext complies with eth.Client interface
+func (c *ReplayClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return notRecorded("CallContext")
+}
+
+// SubscribeNewHead complies with eth.Client interface
+func (c *ReplayClient) SubscribeNewHead(ctx context.Context, ch chan<- *models.Head) (ethereum.Subscription, error) {
+	return nil, notRecorded("SubscribeNewHead")
+}
+
+// ChainID complies with eth.Client interface
+func (c *ReplayClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return nil, notRecorded("ChainID")
+}
+
+// SendTransaction complies with eth.Client interface
+func (c *ReplayClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return notRecorded("SendTransaction")
+}
+
+// PendingCodeAt complies with eth.Client interface
+func (c *ReplayClient) PendingCodeAt(ctx context.Context, account gethCommon.Address) ([]byte, error) {
+	return nil, notRecorded("PendingCodeAt")
+}
+
+// PendingNonceAt complies with eth.Client interface
+func (c *ReplayClient) PendingNonceAt(ctx context.Context, account gethCommon.Address) (uint64, error) {
+	return 0, notRecorded("PendingNonceAt")
+}
+
+// BlockByNumber complies with eth.Client interface
+func (c *ReplayClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return nil, notRecorded("BlockByNumber")
+}
+
+// BalanceAt complies with eth.Client interface
+func (c *ReplayClient) BalanceAt(ctx context.Context, account gethCommon.Address, blockNumber *big.Int) (*big.Int, error) {
+	return nil, notRecorded("BalanceAt")
+}
+
+// SubscribeFilterLogs complies with eth.Client interface
+func (c *ReplayClient) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return nil, notRecorded("SubscribeFilterLogs")
+}
+
+// EstimateGas complies with eth.Client interface
+func (c *ReplayClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return 0, notRecorded("EstimateGas")
+}
+
+// SuggestGasPrice complies with eth.Client interface
+func (c *ReplayClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return nil, notRecorded("SuggestGasPrice")
+}
+
+func batchCalls(b []rpc.BatchElem) []batchElemCall {
+	calls := make([]batchElemCall, len(b))
+	for i, elem := range b {
+		calls[i] = batchElemCall{elem.Method, elem.Args}
+	}
+	return calls
+}
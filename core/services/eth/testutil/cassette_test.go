@@ -0,0 +1,68 @@
+package testutil_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/eth/testutil"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+)
+
+func TestReplayClient_ServesRecordedCalls(t *testing.T) {
+	ethClient := new(mocks.Client)
+	ethClient.On("HeaderByNumber", mock.Anything, big.NewInt(1)).Return(&models.Head{Number: 1}, nil).Once()
+	ethClient.On("HeaderByNumber", mock.Anything, big.NewInt(2)).Return(nil, errors.New("node down")).Once()
+
+	recorder := testutil.NewRecordingClient(ethClient)
+	_, err := recorder.HeaderByNumber(context.Background(), big.NewInt(1))
+	require.NoError(t, err)
+	_, err = recorder.HeaderByNumber(context.Background(), big.NewInt(2))
+	require.EqualError(t, err, "node down")
+	ethClient.AssertExpectations(t)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	require.NoError(t, recorder.Save(path))
+	replayer, err := testutil.NewReplayClient(path)
+	require.NoError(t, err)
+
+	head, err := replayer.HeaderByNumber(context.Background(), big.NewInt(1))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), head.Number)
+	_, err = replayer.HeaderByNumber(context.Background(), big.NewInt(2))
+	require.EqualError(t, err, "node down")
+	_, err = replayer.HeaderByNumber(context.Background(), big.NewInt(3))
+	require.Equal(t, testutil.ErrNotInCassette, errors.Cause(err))
+}
+
+func TestReplayClient_UnrecordedMethods(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	require.NoError(t, testutil.NewRecordingClient(new(mocks.Client)).Save(path))
+	replayer, err := testutil.NewReplayClient(path)
+	require.NoError(t, err)
+
+	_, err = replayer.ChainID(context.Background())
+	require.Equal(t, testutil.ErrNotInCassette, errors.Cause(err))
+	_, err = replayer.BalanceAt(context.Background(), cltest.NewAddress(), nil)
+	require.Equal(t, testutil.ErrNotInCassette, errors.Cause(err))
+	err = replayer.CallContext(context.Background(), nil, "eth_chainId")
+	require.Equal(t, testutil.ErrNotInCassette, errors.Cause(err))
+}
+
+func TestRecordingClient_UnencodableResult(t *testing.T) {
+	ethClient := new(mocks.Client)
+	ethClient.On("BatchCallContext", mock.Anything, mock.Anything).Return(nil).Once()
+
+	b := []rpc.BatchElem{{Method: "eth_chainId", Result: make(chan int)}}
+	err := testutil.NewRecordingClient(ethClient).BatchCallContext(context.Background(), b)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "could not encode result of batched eth_chainId")
+}
@@ -144,6 +144,23 @@ func (gu *gasUpdater) percentileGasPrice() int64 {
 	return gasPrices[idx]
 }
 
+// LegacyGasPriceStats returns the lowest, median and highest gas price of
+// the transactions in the block, or nils if it has none. Every transaction
+// decoded by the go-ethereum version the node uses is a legacy transaction,
+// so none are skipped.
+func LegacyGasPriceStats(block *types.Block) (min, median, max *big.Int) {
+	txs := block.Transactions()
+	if len(txs) == 0 {
+		return nil, nil, nil
+	}
+	gasPrices := make([]*big.Int, len(txs))
+	for i, tx := range txs {
+		gasPrices[i] = tx.GasPrice()
+	}
+	sort.Slice(gasPrices, func(i, j int) bool { return gasPrices[i].Cmp(gasPrices[j]) < 0 })
+	return gasPrices[0], gasPrices[(len(gasPrices)-1)/2], gasPrices[len(gasPrices)-1]
+}
+
 func (gu *gasUpdater) setPercentileGasPrice(gasPrice int64) error {
 	bigGasPrice := big.NewInt(gasPrice)
 	if bigGasPrice.Cmp(gu.store.Config.EthMaxGasPriceWei()) > 0 {
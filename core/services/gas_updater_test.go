@@ -180,3 +180,20 @@ func TestGasUpdater_OnNewLongestChain_WillNotSetGasHigherThanEthMaxGasPriceWei(t
 
 	assert.Equal(t, big.NewInt(42), config.EthGasPriceDefault())
 }
+
+func TestLegacyGasPriceStats(t *testing.T) {
+	min, median, max := services.LegacyGasPriceStats(cltest.BlockWithTransactions(300, 100, 500, 200, 400))
+	assert.Equal(t, big.NewInt(100), min)
+	assert.Equal(t, big.NewInt(300), median)
+	assert.Equal(t, big.NewInt(500), max)
+
+	min, median, max = services.LegacyGasPriceStats(cltest.BlockWithTransactions(200, 100, 200, 100))
+	assert.Equal(t, big.NewInt(100), min)
+	assert.Equal(t, big.NewInt(100), median)
+	assert.Equal(t, big.NewInt(200), max)
+
+	min, median, max = services.LegacyGasPriceStats(cltest.BlockWithTransactions())
+	assert.Nil(t, min)
+	assert.Nil(t, median)
+	assert.Nil(t, max)
+}
@@ -0,0 +1,201 @@
+// Package testutil provides an in-memory log.Broadcaster for tests.
+//
+// It is test-only: it has no persistence, no backfill and no confirmations,
+// and must not be used outside of tests.
+package testutil
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/smartcontractkit/chainlink/core/services/log"
+	"github.com/smartcontractkit/chainlink/core/utils"
+)
+
+type (
+	// Broadcaster is an in-memory log.Broadcaster. Logs pushed with Broadcast
+	// are decoded by the registered contract and handed synchronously to
+	// every listener registered for the log's address, and consumption is
+	// tracked in memory per (block hash, log index, job).
+	Broadcaster struct {
+		utils.DependentAwaiter
+
+		mu            sync.Mutex
+		connected     bool
+		registrations []registration
+		consumed      map[consumptionKey]struct{}
+	}
+
+	registration struct {
+		contract log.AbigenContract
+		listener log.Listener
+	}
+
+	consumptionKey struct {
+		blockHash common.Hash
+		logIndex  uint
+		jobID     interface{}
+	}
+
+	broadcast struct {
+		b          *Broadcaster
+		decodedLog interface{}
+		rawLog     types.Log
+		jobID      interface{}
+	}
+)
+
+var _ log.Broadcaster = (*Broadcaster)(nil)
+
+// NewBroadcaster returns a connected in-memory broadcaster with no
+// registrations
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{
+		DependentAwaiter: utils.NewDependentAwaiter(),
+		connected:        true,
+		consumed:         make(map[consumptionKey]struct{}),
+	}
+}
+
+func (b *Broadcaster) Start() error { return nil }
+func (b *Broadcaster) Stop() error  { return nil }
+
+// Register records the listener for the contract's address and reports
+// whether the broadcaster is currently connected
+func (b *Broadcaster) Register(contract log.AbigenContract, listener log.Listener) (connected bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.registrations = append(b.registrations, registration{contract, listener})
+	return b.connected
+}
+
+// Unregister removes every registration of the listener for the contract's
+// address
+func (b *Broadcaster) Unregister(contract log.AbigenContract, listener log.Listener) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	kept := b.registrations[:0]
+	for _, r := range b.registrations {
+		if r.listener == listener && r.contract.Address() == contract.Address() {
+			continue
+		}
+		kept = append(kept, r)
+	}
+	b.registrations = kept
+}
+
+// Listeners returns the listeners currently registered for the address
+func (b *Broadcaster) Listeners(address common.Address) (listeners []log.Listener) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, r := range b.registrations {
+		if r.contract.Address() == address {
+			listeners = append(listeners, r.listener)
+		}
+	}
+	return listeners
+}
+
+// SetConnected changes the connection state, calling OnConnect or
+// OnDisconnect on every registered listener if it changed
+func (b *Broadcaster) SetConnected(connected bool) {
+	b.mu.Lock()
+	if b.connected == connected {
+		b.mu.Unlock()
+		return
+	}
+	b.connected = connected
+	registrations := append([]registration(nil), b.registrations...)
+	b.mu.Unlock()
+
+	for _, r := range registrations {
+		if connected {
+			r.listener.OnConnect()
+		} else {
+			r.listener.OnDisconnect()
+		}
+	}
+}
+
+// Broadcast hands the log to every listener registered for its address, in
+// registration order, and returns the number of listeners it was delivered
+// to. As with the real broadcaster, listeners whose contract cannot decode
+// the log are skipped.
+func (b *Broadcaster) Broadcast(rawLog types.Log) int {
+	b.mu.Lock()
+	registrations := append([]registration(nil), b.registrations...)
+	b.mu.Unlock()
+
+	delivered := 0
+	for _, r := range registrations {
+		if r.contract.Address() != rawLog.Address {
+			continue
+		}
+		logCopy := copyLog(rawLog)
+		decodedLog, err := r.contract.ParseLog(logCopy)
+		if err != nil {
+			continue
+		}
+		r.listener.HandleLog(&broadcast{
+			b:          b,
+			decodedLog: decodedLog,
+			rawLog:     logCopy,
+			jobID:      jobIDOf(r.listener),
+		}, nil)
+		delivered++
+	}
+	return delivered
+}
+
+// WasConsumed reports whether the log was marked consumed by the listener
+func (b *Broadcaster) WasConsumed(rawLog types.Log, listener log.Listener) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, consumed := b.consumed[consumptionKey{rawLog.BlockHash, rawLog.Index, jobIDOf(listener)}]
+	return consumed
+}
+
+func (lb *broadcast) DecodedLog() interface{} {
+	return lb.decodedLog
+}
+
+func (lb *broadcast) RawLog() types.Log {
+	return lb.rawLog
+}
+
+func (lb *broadcast) SetDecodedLog(newLog interface{}) {
+	lb.decodedLog = newLog
+}
+
+func (lb *broadcast) WasAlreadyConsumed() (bool, error) {
+	lb.b.mu.Lock()
+	defer lb.b.mu.Unlock()
+	_, consumed := lb.b.consumed[lb.key()]
+	return consumed, nil
+}
+
+func (lb *broadcast) MarkConsumed() error {
+	lb.b.mu.Lock()
+	defer lb.b.mu.Unlock()
+	lb.b.consumed[lb.key()] = struct{}{}
+	return nil
+}
+
+func (lb *broadcast) key() consumptionKey {
+	return consumptionKey{lb.rawLog.BlockHash, lb.rawLog.Index, lb.jobID}
+}
+
+func jobIDOf(listener log.Listener) interface{} {
+	if listener.IsV2Job() {
+		return listener.JobIDV2()
+	}
+	return listener.JobID()
+}
+
+func copyLog(l types.Log) types.Log {
+	cpy := l
+	cpy.Topics = append([]common.Hash(nil), l.Topics...)
+	cpy.Data = append([]byte(nil), l.Data...)
+	return cpy
+}
@@ -0,0 +1,109 @@
+package testutil_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/services/log"
+	"github.com/smartcontractkit/chainlink/core/services/log/testutil"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+)
+
+type fakeContract struct {
+	address common.Address
+	err     error
+}
+
+func (c fakeContract) Address() common.Address { return c.address }
+func (c fakeContract) ParseLog(l types.Log) (interface{}, error) {
+	return l.Data, c.err
+}
+
+type recordingListener struct {
+	jobID      models.JobID
+	broadcasts []log.Broadcast
+	connected  bool
+}
+
+func (l *recordingListener) HandleLog(lb log.Broadcast, err error) {
+	l.broadcasts = append(l.broadcasts, lb)
+}
+func (l *recordingListener) OnConnect()          { l.connected = true }
+func (l *recordingListener) OnDisconnect()       { l.connected = false }
+func (l *recordingListener) JobID() models.JobID { return l.jobID }
+func (l *recordingListener) IsV2Job() bool       { return false }
+func (l *recordingListener) JobIDV2() int32      { return 0 }
+
+func TestBroadcaster_ConsumedTracking(t *testing.T) {
+	lb := testutil.NewBroadcaster()
+	contract := fakeContract{address: cltest.NewAddress()}
+	listenerA := &recordingListener{jobID: models.NewJobID()}
+	listenerB := &recordingListener{jobID: models.NewJobID()}
+	require.True(t, lb.Register(contract, listenerA))
+	require.True(t, lb.Register(contract, listenerB))
+
+	rawLog := types.Log{Address: contract.address, BlockHash: cltest.NewHash(), Index: 3, Data: []byte{1}}
+	require.Equal(t, 2, lb.Broadcast(rawLog))
+	require.Len(t, listenerA.broadcasts, 1)
+	require.Len(t, listenerB.broadcasts, 1)
+	assert.Equal(t, []byte{1}, listenerA.broadcasts[0].DecodedLog())
+
+	consumed, err := listenerA.broadcasts[0].WasAlreadyConsumed()
+	require.NoError(t, err)
+	assert.False(t, consumed)
+
+	require.NoError(t, listenerA.broadcasts[0].MarkConsumed())
+	consumed, err = listenerA.broadcasts[0].WasAlreadyConsumed()
+	require.NoError(t, err)
+	assert.True(t, consumed)
+	assert.True(t, lb.WasConsumed(rawLog, listenerA))
+
+	// Consumption is per job
+	consumed, err = listenerB.broadcasts[0].WasAlreadyConsumed()
+	require.NoError(t, err)
+	assert.False(t, consumed)
+	assert.False(t, lb.WasConsumed(rawLog, listenerB))
+
+	// Consumption is per log, and survives redelivery
+	require.Equal(t, 2, lb.Broadcast(rawLog))
+	consumed, err = listenerA.broadcasts[1].WasAlreadyConsumed()
+	require.NoError(t, err)
+	assert.True(t, consumed)
+
+	otherLog := rawLog
+	otherLog.Index = 4
+	assert.False(t, lb.WasConsumed(otherLog, listenerA))
+}
+
+func TestBroadcaster_RegistrationsAndConnection(t *testing.T) {
+	lb := testutil.NewBroadcaster()
+	contract := fakeContract{address: cltest.NewAddress()}
+	other := fakeContract{address: cltest.NewAddress()}
+	undecodable := fakeContract{address: cltest.NewAddress(), err: errors.New("cannot decode")}
+	listener := &recordingListener{jobID: models.NewJobID()}
+
+	lb.Register(contract, listener)
+	lb.Register(undecodable, listener)
+	assert.Equal(t, []log.Listener{listener}, lb.Listeners(contract.address))
+	assert.Empty(t, lb.Listeners(other.address))
+
+	assert.Equal(t, 0, lb.Broadcast(types.Log{Address: other.address}))
+	assert.Equal(t, 0, lb.Broadcast(types.Log{Address: undecodable.address}))
+	assert.Empty(t, listener.broadcasts)
+
+	lb.SetConnected(false)
+	assert.False(t, listener.connected)
+	assert.False(t, lb.Register(other, listener))
+	lb.SetConnected(true)
+	assert.True(t, listener.connected)
+
+	lb.Unregister(contract, listener)
+	assert.Empty(t, lb.Listeners(contract.address))
+	assert.Equal(t, 0, lb.Broadcast(types.Log{Address: contract.address}))
+}
@@ -0,0 +1,86 @@
+package offchainreporting
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultBlockTimestampCacheSize is the number of block timestamps cached by
+// default
+const DefaultBlockTimestampCacheSize = 256
+
+// WithBlockTimestampCacheSize sets the number of block timestamps that
+// BlockTimestamp caches, the least recently used being evicted first
+func WithBlockTimestampCacheSize(size int) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.blockTimestamps = newBlockTimestampCache(size)
+	}
+}
+
+// blockTimestampCache is an LRU cache of block timestamps by block number
+type blockTimestampCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[uint64]*list.Element
+	order   *list.List
+}
+
+type blockTimestamp struct {
+	blockNumber uint64
+	timestamp   time.Time
+}
+
+func newBlockTimestampCache(size int) *blockTimestampCache {
+	return &blockTimestampCache{
+		size:    size,
+		entries: make(map[uint64]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *blockTimestampCache) get(blockNumber uint64) (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, exists := c.entries[blockNumber]
+	if !exists {
+		return time.Time{}, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(blockTimestamp).timestamp, true
+}
+
+func (c *blockTimestampCache) add(blockNumber uint64, timestamp time.Time) {
+	if c.size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, exists := c.entries[blockNumber]; exists {
+		elem.Value = blockTimestamp{blockNumber, timestamp}
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[blockNumber] = c.order.PushFront(blockTimestamp{blockNumber, timestamp})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(blockTimestamp).blockNumber)
+	}
+}
+
+// BlockTimestamp returns the timestamp of the block, for handled logs which
+// only carry the block number. Timestamps are cached by block number, so the
+// header of a block is only fetched again once it has been evicted.
+func (oc *OCRContractConfigTracker) BlockTimestamp(ctx context.Context, blockNumber uint64) (time.Time, error) {
+	if timestamp, exists := oc.blockTimestamps.get(blockNumber); exists {
+		return timestamp, nil
+	}
+	h, err := oc.headerByNumber(ctx, blockNumber)
+	if err != nil {
+		return time.Time{}, oc.wrapErr(err, "BlockTimestamp failed")
+	}
+	oc.blockTimestamps.add(blockNumber, h.Timestamp)
+	return h.Timestamp, nil
+}
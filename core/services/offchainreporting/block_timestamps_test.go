@@ -0,0 +1,56 @@
+package offchainreporting_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OCRContractConfigTracker_BlockTimestamp(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, _ := newTestTracker(t, ethClient, new(logmocks.Broadcaster), offchainreporting.WithBlockTimestampCacheSize(2))
+	timestamp := func(blockNumber int64) time.Time {
+		return time.Unix(1600000000+blockNumber, 0).UTC()
+	}
+	expectHeader := func(blockNumber int64) {
+		ethClient.On("HeaderByNumber", mock.Anything, big.NewInt(blockNumber)).Return(&models.Head{Number: blockNumber, Timestamp: timestamp(blockNumber)}, nil).Once()
+	}
+
+	expectHeader(1)
+	expectHeader(2)
+	for i := 0; i < 3; i++ {
+		for _, n := range []int64{1, 2} {
+			ts, err := tracker.BlockTimestamp(context.Background(), uint64(n))
+			require.NoError(t, err)
+			require.Equal(t, timestamp(n), ts)
+		}
+	}
+	ethClient.AssertExpectations(t)
+
+	// Block 1 is the least recently used, so is evicted for block 3
+	expectHeader(3)
+	_, err := tracker.BlockTimestamp(context.Background(), 3)
+	require.NoError(t, err)
+	_, err = tracker.BlockTimestamp(context.Background(), 2)
+	require.NoError(t, err)
+	expectHeader(1)
+	ts, err := tracker.BlockTimestamp(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, timestamp(1), ts)
+
+	ethClient.On("HeaderByNumber", mock.Anything, big.NewInt(4)).Return(nil, errors.New("rpc down")).Once()
+	_, err = tracker.BlockTimestamp(context.Background(), 4)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "rpc down")
+
+	ethClient.AssertExpectations(t)
+}
@@ -0,0 +1,147 @@
+package offchainreporting
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/utils"
+)
+
+// ErrCircuitOpen is returned instead of making an RPC call while the
+// tracker's circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open after repeated RPC failures")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// rpcOutcomeHistorySize is the number of recent RPC call outcomes kept for
+// RPCErrorRate
+const rpcOutcomeHistorySize = 256
+
+type rpcOutcome struct {
+	at     time.Time
+	failed bool
+}
+
+// circuitBreaker fails RPC calls fast once failureThreshold consecutive calls
+// have failed. After cooldown has elapsed a single probe call is let through;
+// if it succeeds the circuit closes again, otherwise it re-opens.
+//
+// A failureThreshold of 0 disables the breaker.
+type circuitBreaker struct {
+	failureThreshold uint32
+	cooldown         time.Duration
+	clock            utils.Nower
+
+	mu                  sync.Mutex
+	state               circuitState
+	consecutiveFailures uint32
+	totalFailures       uint64
+	openedAt            time.Time
+	lastErr             error
+
+	// outcomes is a ring buffer of the most recent calls, the next of which
+	// is written at nextOutcome
+	outcomes    [rpcOutcomeHistorySize]rpcOutcome
+	nextOutcome int
+	numOutcomes int
+}
+
+func (cb *circuitBreaker) call(fn func() error) error {
+	if !cb.allow() {
+		return ErrCircuitOpen
+	}
+	err := fn()
+	cb.record(err)
+	return err
+}
+
+func (cb *circuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch cb.state {
+	case circuitOpen:
+		if cb.clock.Now().Sub(cb.openedAt) < cb.cooldown {
+			return false
+		}
+		cb.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		// A probe call is already in flight
+		return false
+	default:
+		return true
+	}
+}
+
+func (cb *circuitBreaker) record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.outcomes[cb.nextOutcome] = rpcOutcome{cb.clock.Now(), err != nil}
+	cb.nextOutcome = (cb.nextOutcome + 1) % rpcOutcomeHistorySize
+	if cb.numOutcomes < rpcOutcomeHistorySize {
+		cb.numOutcomes++
+	}
+	if err == nil {
+		cb.state = circuitClosed
+		cb.consecutiveFailures = 0
+		cb.lastErr = nil
+		return
+	}
+	cb.consecutiveFailures++
+	cb.totalFailures++
+	cb.lastErr = err
+	if cb.failureThreshold == 0 {
+		return
+	}
+	if cb.state == circuitHalfOpen || cb.consecutiveFailures >= cb.failureThreshold {
+		cb.state = circuitOpen
+		cb.openedAt = cb.clock.Now()
+	}
+}
+
+func (cb *circuitBreaker) healthy() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.state == circuitOpen {
+		return errors.Wrapf(cb.lastErr, "circuit breaker opened after %d consecutive RPC failures", cb.consecutiveFailures)
+	}
+	return nil
+}
+
+// stats returns the total and consecutive number of failed calls, and
+// whether the circuit is open
+func (cb *circuitBreaker) stats() (totalFailures uint64, consecutiveFailures uint32, open bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.totalFailures, cb.consecutiveFailures, cb.state == circuitOpen
+}
+
+// errorRate returns the fraction of the calls made within the window that
+// failed, out of at most the last rpcOutcomeHistorySize calls
+func (cb *circuitBreaker) errorRate(window time.Duration) float64 {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	since := cb.clock.Now().Add(-window)
+	var calls, failures int
+	for i := 1; i <= cb.numOutcomes; i++ {
+		outcome := cb.outcomes[(cb.nextOutcome-i+rpcOutcomeHistorySize)%rpcOutcomeHistorySize]
+		if outcome.at.Before(since) {
+			break
+		}
+		calls++
+		if outcome.failed {
+			failures++
+		}
+	}
+	if calls == 0 {
+		return 0
+	}
+	return float64(failures) / float64(calls)
+}
@@ -0,0 +1,72 @@
+package offchainreporting_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OCRContractConfigTracker_CircuitBreaker(t *testing.T) {
+	ethClient := new(mocks.Client)
+	clock := newFakeClock()
+	tracker, _ := newTestTracker(t, ethClient, new(logmocks.Broadcaster),
+		offchainreporting.WithClock(clock),
+		offchainreporting.WithCircuitBreaker(2, time.Minute),
+	)
+
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(nil, errors.New("rpc down")).Times(2)
+	for i := 0; i < 2; i++ {
+		_, err := tracker.LatestBlockHeight(context.Background())
+		require.EqualError(t, errors.Cause(err), "rpc down")
+	}
+	require.Error(t, tracker.Healthy())
+
+	// Fails fast without hitting the RPC
+	_, err := tracker.LatestBlockHeight(context.Background())
+	require.Equal(t, offchainreporting.ErrCircuitOpen, errors.Cause(err))
+
+	clock.Advance(time.Minute)
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(&models.Head{Number: 42}, nil).Once()
+	height, err := tracker.LatestBlockHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), height)
+	require.NoError(t, tracker.Healthy())
+
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_RPCErrorRate(t *testing.T) {
+	ethClient := new(mocks.Client)
+	clock := newFakeClock()
+	tracker, _ := newTestTracker(t, ethClient, new(logmocks.Broadcaster), offchainreporting.WithClock(clock))
+	require.Equal(t, float64(0), tracker.RPCErrorRate(time.Minute))
+
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(nil, errors.New("rpc down")).Times(2)
+	for i := 0; i < 2; i++ {
+		_, err := tracker.LatestBlockHeight(context.Background())
+		require.Error(t, err)
+	}
+	require.Equal(t, float64(1), tracker.RPCErrorRate(time.Minute))
+
+	clock.Advance(time.Minute)
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(&models.Head{Number: 42}, nil).Times(2)
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(nil, errors.New("rpc down")).Once()
+	for i := 0; i < 3; i++ {
+		_, _ = tracker.LatestBlockHeight(context.Background())
+	}
+
+	require.InDelta(t, float64(1)/3, tracker.RPCErrorRate(30*time.Second), 1e-9)
+	require.InDelta(t, float64(3)/5, tracker.RPCErrorRate(2*time.Minute), 1e-9)
+	clock.Advance(time.Hour)
+	require.Equal(t, float64(0), tracker.RPCErrorRate(time.Minute))
+	ethClient.AssertExpectations(t)
+}
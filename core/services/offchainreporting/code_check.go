@@ -0,0 +1,69 @@
+package offchainreporting
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/utils"
+)
+
+// ErrContractHasNoCode is returned instead of making an RPC call while the
+// contract is known to have no code, e.g. after it self-destructed
+var ErrContractHasNoCode = errors.New("contract has no code")
+
+// WithCodeCheck makes subscriptions check every interval that there is still
+// code at the contract address. While there is none, Healthy reports
+// ErrContractHasNoCode and RPC reads fail fast with it instead of failing
+// cryptically at the node, until a later check finds code again.
+func WithCodeCheck(interval time.Duration) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.codeCheckInterval = interval
+	}
+}
+
+func (sub *OCRContractConfigSubscription) runCodeCheck() {
+	ctx, cancel := utils.ContextFromChan(sub.chStop)
+	defer cancel()
+	for {
+		select {
+		case <-sub.oc.clock.After(sub.oc.codeCheckInterval):
+			if err := sub.oc.CheckCode(ctx); err != nil && errors.Cause(err) != ErrContractHasNoCode {
+				sub.logger.Debugw("OCRContract: could not check contract code", "err", err)
+			}
+		case <-sub.chStop:
+			return
+		}
+	}
+}
+
+// CheckCode fetches the code at the contract address, returning
+// ErrContractHasNoCode if there is none. The result is remembered, so that
+// while the contract has no code Healthy reports it and RPC reads fail fast.
+func (oc *OCRContractConfigTracker) CheckCode(ctx context.Context) error {
+	var code []byte
+	err := oc.callUnchecked(ctx, func() error {
+		return oc.withFailover(ctx, func(e endpoint) (err2 error) {
+			code, err2 = e.client.CodeAt(ctx, oc.contract.Address(), nil)
+			return err2
+		})
+	})
+	if err != nil {
+		return oc.wrapErr(err, "could not fetch contract code")
+	}
+	if len(code) == 0 {
+		if atomic.SwapUint32(&oc.noCode, 1) == 0 {
+			oc.logger.Errorw("OCRContract: contract has no code, pausing RPC reads until it does")
+		}
+		return ErrContractHasNoCode
+	}
+	if atomic.SwapUint32(&oc.noCode, 0) == 1 {
+		oc.logger.Infow("OCRContract: contract has code again, resuming RPC reads")
+	}
+	return nil
+}
+
+func (oc *OCRContractConfigTracker) hasNoCode() bool {
+	return atomic.LoadUint32(&oc.noCode) == 1
+}
@@ -0,0 +1,42 @@
+package offchainreporting_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OCRContractConfigTracker_CheckCode(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, address := newTestTracker(t, ethClient, new(logmocks.Broadcaster))
+
+	ethClient.On("CodeAt", mock.Anything, address, (*big.Int)(nil)).Return([]byte{}, nil).Once()
+	err := tracker.CheckCode(context.Background())
+	require.Equal(t, offchainreporting.ErrContractHasNoCode, err)
+	err = tracker.Healthy()
+	require.Error(t, err)
+	require.Equal(t, offchainreporting.ErrContractHasNoCode, errors.Cause(err))
+
+	// Reads fail fast without hitting the RPC
+	_, _, err = tracker.LatestConfigDetails(context.Background())
+	require.Equal(t, offchainreporting.ErrContractHasNoCode, errors.Cause(err))
+	ethClient.AssertNotCalled(t, "CallContract", mock.Anything, mock.Anything, mock.Anything)
+
+	ethClient.On("CodeAt", mock.Anything, address, (*big.Int)(nil)).Return([]byte{1, 2, 3}, nil).Once()
+	require.NoError(t, tracker.CheckCode(context.Background()))
+	require.NoError(t, tracker.Healthy())
+
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 1, 42, [16]byte{1}), nil).Once()
+	changedInBlock, _, err := tracker.LatestConfigDetails(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), changedInBlock)
+
+	ethClient.AssertExpectations(t)
+}
@@ -0,0 +1,62 @@
+package offchainreporting
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/libocr/gethwrappers/offchainaggregator"
+	"github.com/smartcontractkit/libocr/offchainreporting/confighelper"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+)
+
+// VerifyConfigDigest recomputes the config digest from the fields of the
+// ConfigSet event, using the same algorithm as libocr and the contract, and
+// returns an error if it does not match the expected digest.
+//
+// The ConfigSet event does not itself carry the digest, so the expected value
+// has to come from elsewhere, typically the contract's latestConfigDetails.
+func VerifyConfigDigest(configSet offchainaggregator.OffchainAggregatorConfigSet, expected ocrtypes.ConfigDigest) error {
+	actual := confighelper.ContractConfigFromConfigSetEvent(configSet).ConfigDigest
+	if actual != expected {
+		return errors.Errorf("config digest mismatch: computed %x from ConfigSet log in block %d, expected %x", actual, configSet.Raw.BlockNumber, expected)
+	}
+	return nil
+}
+
+// verifyConfigSet checks the digest of the config set by raw. The digest is
+// recomputed from the log with VerifyConfigDigest, unless a custom
+// ConfigSetParser is used for logs that do not have the OffchainAggregator
+// layout. The latest config is additionally checked against the digest
+// reported by the contract; a config that has since been superseded (or is
+// not yet visible to the node's call endpoint) cannot be checked that way.
+func (oc *OCRContractConfigTracker) verifyConfigSet(ctx context.Context, cc ocrtypes.ContractConfig, raw types.Log) error {
+	recomputed := false
+	if configSet, err := parseConfigSetEvent(raw); err == nil {
+		if err = VerifyConfigDigest(*configSet, cc.ConfigDigest); err != nil {
+			return err
+		}
+		recomputed = true
+	} else if oc.configSetParser == nil {
+		return errors.Wrap(err, "could not parse ConfigSet log to recompute its digest")
+	}
+
+	// The cache may still hold details from before this config was set
+	oc.invalidateConfigDetailsCache()
+	changedInBlock, digest, err := oc.LatestConfigDetails(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not fetch config digest from contract")
+	}
+	if changedInBlock != raw.BlockNumber {
+		if recomputed {
+			oc.logger.Debugw("OCRContract: config set is not the latest, verified recomputed digest only", "blockNumber", raw.BlockNumber, "latestConfigBlockNumber", changedInBlock)
+		} else {
+			oc.logger.Warnw("OCRContract: could not verify digest of config set that is not the latest, custom parser's digest cannot be recomputed", "blockNumber", raw.BlockNumber, "latestConfigBlockNumber", changedInBlock)
+		}
+		return nil
+	}
+	if cc.ConfigDigest != digest {
+		return errors.Errorf("config digest mismatch: got %x from ConfigSet log in block %d, expected %x", cc.ConfigDigest, raw.BlockNumber, digest)
+	}
+	return nil
+}
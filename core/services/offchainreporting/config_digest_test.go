@@ -0,0 +1,91 @@
+package offchainreporting_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/log"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/smartcontractkit/libocr/gethwrappers/offchainaggregator"
+	"github.com/smartcontractkit/libocr/offchainreporting/confighelper"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_VerifyConfigDigest(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly(), offchainreporting.WithConfigDigestVerification())
+
+	contractFilterer, err := offchainaggregator.NewOffchainAggregatorFilterer(address, ethClient)
+	require.NoError(t, err)
+	raw := newConfigSetLog(t, address, 10, 1)
+	configSet, err := contractFilterer.ParseConfigSet(raw)
+	require.NoError(t, err)
+	digest := confighelper.ContractConfigFromConfigSetEvent(*configSet).ConfigDigest
+
+	require.NoError(t, offchainreporting.VerifyConfigDigest(*configSet, digest))
+	tampered := *configSet
+	tampered.Threshold++
+	require.Error(t, offchainreporting.VerifyConfigDigest(tampered, digest))
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	// The contract reports a different digest for the block, so the log is
+	// rejected and left unconsumed
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 1, 10, [16]byte{0xff}), nil).Once()
+	broadcast := newBroadcast(raw)
+	sub.(log.Listener).HandleLog(broadcast, nil)
+	broadcast.AssertNotCalled(t, "MarkConsumed")
+	require.Len(t, tracker.ConfigHistory(), 0)
+
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 1, 10, digest), nil).Once()
+	broadcast = newBroadcast(raw)
+	sub.(log.Listener).HandleLog(broadcast, nil)
+	broadcast.AssertCalled(t, "MarkConsumed")
+	require.Len(t, tracker.ConfigHistory(), 1)
+
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_ConfigDigestVerification_NotLatest(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	// The parser returns a digest that does not match the log's contents
+	tampering := func(raw types.Log) (ocrtypes.ContractConfig, error) {
+		cc, err := offchainreporting.ParseOCRConfigSet(raw)
+		cc.ConfigDigest = ocrtypes.ConfigDigest{0xee}
+		return cc, err
+	}
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly(), offchainreporting.WithConfigDigestVerification(), offchainreporting.WithConfigSetParser(tampering))
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	// The contract's latest config is from a later block, but the recomputed
+	// digest still does not match
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 2, 20, [16]byte{2}), nil)
+	broadcast := newBroadcast(newConfigSetLog(t, address, 10, 1))
+	sub.(log.Listener).HandleLog(broadcast, nil)
+	broadcast.AssertNotCalled(t, "MarkConsumed")
+	require.Len(t, tracker.ConfigHistory(), 0)
+	_, found := tracker.LatestConfigDigest()
+	require.False(t, found)
+}
+
+func Test_OCRContractConfigTracker_ConfigDigestVerification_NotLatest_Valid(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly(), offchainreporting.WithConfigDigestVerification())
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 2, 20, [16]byte{2}), nil).Once()
+	broadcast := newBroadcast(newConfigSetLog(t, address, 10, 1))
+	sub.(log.Listener).HandleLog(broadcast, nil)
+	broadcast.AssertCalled(t, "MarkConsumed")
+	require.Len(t, tracker.ConfigHistory(), 1)
+	ethClient.AssertExpectations(t)
+}
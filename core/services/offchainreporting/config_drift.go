@@ -0,0 +1,68 @@
+package offchainreporting
+
+import (
+	"context"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/utils"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+)
+
+// WithConfigDriftCheck makes subscriptions compare the contract's config
+// digest against the digest of the latest config seen by the tracker every
+// interval. If they differ for longer than gracePeriod, logs have most likely
+// been missed and the tracker is stuck on a stale config, so an error is
+// logged and the config drift metric is set.
+func WithConfigDriftCheck(interval, gracePeriod time.Duration) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.configDriftInterval = interval
+		oc.configDriftGracePeriod = gracePeriod
+	}
+}
+
+func (sub *OCRContractConfigSubscription) runConfigDriftCheck() {
+	ctx, cancel := utils.ContextFromChan(sub.chStop)
+	defer cancel()
+	for {
+		select {
+		case <-sub.oc.clock.After(sub.oc.configDriftInterval):
+			sub.checkConfigDrift(ctx)
+		case <-sub.chStop:
+			return
+		}
+	}
+}
+
+// checkConfigDrift compares the on-chain config digest with the latest seen,
+// tracking since when they have differed
+func (sub *OCRContractConfigSubscription) checkConfigDrift(ctx context.Context) {
+	_, onChain, err := sub.oc.LatestConfigDetails(ctx)
+	if err != nil {
+		sub.logger.Debugw("OCRContract: could not fetch config details to check for drift", "err", err)
+		return
+	}
+	var seen ocrtypes.ConfigDigest
+	if cc := sub.oc.getLatestConfig(); cc != nil {
+		seen = cc.ConfigDigest
+	}
+	labels := map[string]string{"contract_address": sub.contract.Address().Hex()}
+
+	now := sub.oc.clock.Now()
+	if onChain == seen {
+		if !sub.driftSince.IsZero() && now.Sub(sub.driftSince) >= sub.oc.configDriftGracePeriod {
+			sub.logger.Infow("OCRContract: config drift resolved", "configDigest", onChain)
+			sub.oc.metrics.SetGauge(MetricConfigDrift, 0, labels)
+		}
+		sub.driftSince = time.Time{}
+		return
+	}
+	if sub.driftSince.IsZero() {
+		sub.driftSince = now
+	}
+	if now.Sub(sub.driftSince) < sub.oc.configDriftGracePeriod {
+		return
+	}
+	sub.logger.Errorw("OCRContract: on-chain config digest differs from latest config seen, logs may have been missed",
+		"onChainConfigDigest", onChain, "seenConfigDigest", seen, "driftingSince", sub.driftSince)
+	sub.oc.metrics.SetGauge(MetricConfigDrift, 1, labels)
+}
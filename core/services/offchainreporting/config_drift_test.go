@@ -0,0 +1,54 @@
+package offchainreporting_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/log"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OCRContractConfigSubscription_ConfigDriftCheck(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	sink := &fakeMetricsSink{}
+	clock := newFakeClock()
+	tracker, address := newTestTracker(t, ethClient, lb,
+		offchainreporting.WithReadOnly(),
+		offchainreporting.WithClock(clock),
+		offchainreporting.WithMetricsSink(sink),
+		offchainreporting.WithConfigDriftCheck(time.Second, time.Minute),
+	)
+
+	// The contract has moved on to a config whose log was never received
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 2, 2, [16]byte{9}), nil)
+
+	sub := newTestSubscription(t, tracker, lb)
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 1, 1)), nil)
+
+	driftMetrics := func() (values []float64) {
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		for _, m := range sink.metrics {
+			if m.name == offchainreporting.MetricConfigDrift {
+				values = append(values, m.value)
+			}
+		}
+		return values
+	}
+
+	// Each check is done once the next one is scheduled. Nothing fires within
+	// the grace period.
+	clock.awaitAfter(t, time.Second)
+	clock.Advance(time.Second)
+	clock.awaitAfter(t, time.Second)
+	require.Empty(t, driftMetrics())
+
+	clock.Advance(time.Minute)
+	clock.awaitAfter(t, time.Second)
+	require.Equal(t, []float64{1}, driftMetrics())
+}
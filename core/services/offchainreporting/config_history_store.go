@@ -0,0 +1,112 @@
+package offchainreporting
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+)
+
+type (
+	// ConfigRecord is a contract config applied by the tracker, along with the
+	// block it was set in and the time it was applied
+	ConfigRecord struct {
+		ocrtypes.ContractConfig
+		BlockNumber uint64
+		AppliedAt   time.Time
+	}
+
+	// ConfigHistoryStore persists every config applied by a tracker, so that
+	// the history survives restarts
+	ConfigHistoryStore interface {
+		WriteConfigRecord(ctx context.Context, record ConfigRecord) error
+		ConfigHistoryBetween(ctx context.Context, from, to time.Time) ([]ConfigRecord, error)
+	}
+)
+
+var _ ConfigHistoryStore = &db{}
+
+// NewConfigHistoryStore returns a ConfigHistoryStore scoped to this
+// oracleSpecID
+func NewConfigHistoryStore(sqldb *sql.DB, oracleSpecID int32) ConfigHistoryStore {
+	return &db{sqldb, oracleSpecID}
+}
+
+func (d *db) WriteConfigRecord(ctx context.Context, record ConfigRecord) error {
+	var signers [][]byte
+	var transmitters [][]byte
+	for _, s := range record.Signers {
+		signers = append(signers, s.Bytes())
+	}
+	for _, t := range record.Transmitters {
+		transmitters = append(transmitters, t.Bytes())
+	}
+	_, err := d.ExecContext(ctx, `
+INSERT INTO offchainreporting_contract_config_history (offchainreporting_oracle_spec_id, config_digest, signers, transmitters, threshold, encoded_config_version, encoded, block_number, applied_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+`, d.oracleSpecID, record.ConfigDigest, pq.ByteaArray(signers), pq.ByteaArray(transmitters), record.Threshold, int(record.EncodedConfigVersion), record.Encoded, record.BlockNumber, record.AppliedAt)
+
+	return errors.Wrap(err, "WriteConfigRecord failed")
+}
+
+// ConfigHistoryBetween returns the configs applied in the inclusive time
+// range, oldest first
+func (d *db) ConfigHistoryBetween(ctx context.Context, from, to time.Time) ([]ConfigRecord, error) {
+	rows, err := d.QueryContext(ctx, `
+SELECT config_digest, signers, transmitters, threshold, encoded_config_version, encoded, block_number, applied_at
+FROM offchainreporting_contract_config_history
+WHERE offchainreporting_oracle_spec_id = $1 AND applied_at BETWEEN $2 AND $3
+ORDER BY applied_at ASC, id ASC
+`, d.oracleSpecID, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConfigHistoryBetween failed to query rows")
+	}
+	defer logger.ErrorIfCalling(rows.Close)
+
+	var records []ConfigRecord
+	for rows.Next() {
+		var record ConfigRecord
+		var signers [][]byte
+		var transmitters [][]byte
+		if err := rows.Scan(&record.ConfigDigest, (*pq.ByteaArray)(&signers), (*pq.ByteaArray)(&transmitters), &record.Threshold, &record.EncodedConfigVersion, &record.Encoded, &record.BlockNumber, &record.AppliedAt); err != nil {
+			return nil, errors.Wrap(err, "ConfigHistoryBetween failed to scan row")
+		}
+		for _, s := range signers {
+			record.Signers = append(record.Signers, common.BytesToAddress(s))
+		}
+		for _, t := range transmitters {
+			record.Transmitters = append(record.Transmitters, common.BytesToAddress(t))
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "ConfigHistoryBetween failed to iterate rows")
+	}
+	return records, nil
+}
+
+// WithConfigHistoryStore persists every config applied by the tracker's
+// subscriptions to the store
+func WithConfigHistoryStore(store ConfigHistoryStore) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.configHistoryStore = store
+	}
+}
+
+// persistConfig writes the applied config to the config history store, if
+// any. Failures are logged since the config has already been applied.
+func (oc *OCRContractConfigTracker) persistConfig(ctx context.Context, cc ocrtypes.ContractConfig, blockNumber uint64) {
+	if oc.configHistoryStore == nil {
+		return
+	}
+	record := ConfigRecord{cc, blockNumber, oc.clock.Now()}
+	if err := oc.configHistoryStore.WriteConfigRecord(ctx, record); err != nil {
+		oc.logger.Errorw("OCRContract: could not persist applied config", "err", err, "blockNumber", blockNumber)
+	}
+}
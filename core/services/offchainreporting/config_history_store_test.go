@@ -0,0 +1,73 @@
+package offchainreporting_test
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/log"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeConfigHistoryStore struct {
+	mu      sync.Mutex
+	records []offchainreporting.ConfigRecord
+	// written, if set, receives every record written
+	written chan offchainreporting.ConfigRecord
+}
+
+func (s *fakeConfigHistoryStore) WriteConfigRecord(_ context.Context, record offchainreporting.ConfigRecord) error {
+	s.mu.Lock()
+	s.records = append(s.records, record)
+	s.mu.Unlock()
+	if s.written != nil {
+		s.written <- record
+	}
+	return nil
+}
+
+func (s *fakeConfigHistoryStore) ConfigHistoryBetween(_ context.Context, from, to time.Time) (records []offchainreporting.ConfigRecord, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, record := range s.records {
+		if !record.AppliedAt.Before(from) && !record.AppliedAt.After(to) {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func Test_OCRContractConfigSubscription_ConfigHistoryStore(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	store := &fakeConfigHistoryStore{written: make(chan offchainreporting.ConfigRecord, 1)}
+	clock := newFakeClock()
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithClock(clock), offchainreporting.WithConfigHistoryStore(store))
+
+	sub := newTestSubscription(t, tracker, lb)
+	listener := sub.(log.Listener)
+
+	start := clock.Now()
+	for i := uint64(1); i <= 3; i++ {
+		listener.HandleLog(newBroadcast(newConfigSetLog(t, address, 10*i, i)), nil)
+		<-sub.Configs()
+		// The record is written once the config has been delivered
+		record := <-store.written
+		require.Equal(t, 10*i, record.BlockNumber)
+		clock.Advance(time.Hour)
+	}
+
+	records, err := store.ConfigHistoryBetween(context.Background(), start.Add(time.Minute), start.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, uint64(20), records[0].BlockNumber)
+	require.Equal(t, common.BigToAddress(big.NewInt(2)), records[0].Signers[0])
+	require.Equal(t, start.Add(time.Hour), records[0].AppliedAt)
+	require.Equal(t, uint64(30), records[1].BlockNumber)
+}
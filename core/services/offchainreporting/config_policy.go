@@ -0,0 +1,48 @@
+package offchainreporting
+
+import (
+	"github.com/pkg/errors"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+)
+
+// ErrConfigRejected is returned by ConfigFromLogs if the config fails the
+// tracker's config policy
+var ErrConfigRejected = errors.New("config rejected by config policy")
+
+type (
+	// ConfigPolicy enforces deployment-specific invariants on contract
+	// configs, such as a minimum number of oracles. A config the policy
+	// rejects is never handed to libocr, which keeps running with the
+	// previous config.
+	ConfigPolicy interface {
+		Check(ocrtypes.ContractConfig) error
+	}
+
+	// NoopConfigPolicy accepts every config. It is the default policy.
+	NoopConfigPolicy struct{}
+)
+
+var _ ConfigPolicy = NoopConfigPolicy{}
+
+// Check complies with ConfigPolicy interface
+func (NoopConfigPolicy) Check(ocrtypes.ContractConfig) error {
+	return nil
+}
+
+// WithConfigPolicy sets the policy every config must pass before it is
+// delivered to subscribers
+func WithConfigPolicy(policy ConfigPolicy) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.configPolicy = policy
+	}
+}
+
+// allowedByPolicy reports whether the config passes the tracker's policy,
+// logging the reason if it does not
+func (oc *OCRContractConfigTracker) allowedByPolicy(cc ocrtypes.ContractConfig, blockNumber uint64) bool {
+	if err := oc.configPolicy.Check(cc); err != nil {
+		oc.logger.Errorw("OCRContract: config rejected by config policy, keeping previous config", "err", err, "blockNumber", blockNumber)
+		return false
+	}
+	return true
+}
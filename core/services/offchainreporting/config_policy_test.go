@@ -0,0 +1,94 @@
+package offchainreporting_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/log"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type minOraclesPolicy struct {
+	min int
+}
+
+func (p *minOraclesPolicy) Check(cc ocrtypes.ContractConfig) error {
+	if len(cc.Signers) < p.min {
+		return errors.Errorf("config has %d oracles, need at least %d", len(cc.Signers), p.min)
+	}
+	return nil
+}
+
+func Test_OCRContractConfigSubscription_ConfigPolicy(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	policy := &minOraclesPolicy{min: 1}
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly(), offchainreporting.WithConfigPolicy(policy))
+
+	sub := newTestSubscription(t, tracker, lb)
+	listener := sub.(log.Listener)
+
+	listener.HandleLog(newBroadcast(newConfigSetLog(t, address, 1, 1)), nil)
+	require.Len(t, tracker.ConfigHistory(), 1)
+
+	// The rejected config is consumed but neither delivered nor made the
+	// latest config
+	policy.min = 4
+	broadcast := newBroadcast(newConfigSetLog(t, address, 2, 2))
+	listener.HandleLog(broadcast, nil)
+	broadcast.AssertCalled(t, "MarkConsumed")
+	require.Len(t, tracker.ConfigHistory(), 1)
+	signers, _, err := tracker.CurrentOracles()
+	require.NoError(t, err)
+	require.Equal(t, []common.Address{common.BigToAddress(big.NewInt(1))}, signers)
+}
+
+func Test_OCRContractConfigTracker_ConfigPolicy_ConfigFromLogs(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	policy := &minOraclesPolicy{min: 1}
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly(), offchainreporting.WithConfigPolicy(policy))
+
+	sub := newTestSubscription(t, tracker, lb)
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 1, 1)), nil)
+	digest, found := tracker.LatestConfigDigest()
+	require.True(t, found)
+
+	policy.min = 4
+	ethClient.On("FilterLogs", mock.Anything, mock.Anything).Return([]types.Log{newConfigSetLog(t, address, 2, 2)}, nil).Once()
+	_, err := tracker.ConfigFromLogs(context.Background(), 2)
+	require.Equal(t, offchainreporting.ErrConfigRejected, errors.Cause(err))
+
+	latest, found := tracker.LatestConfigDigest()
+	require.True(t, found)
+	require.Equal(t, digest, latest)
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_ConfigPolicy_InitialFetch(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithEagerInitialFetch(), offchainreporting.WithConfigPolicy(&minOraclesPolicy{min: 4}))
+
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 3, 42, [16]byte{3}), nil).Once()
+	fetched := make(chan struct{})
+	ethClient.On("FilterLogs", mock.Anything, mock.Anything).Return([]types.Log{newConfigSetLog(t, address, 42, 3)}, nil).Once().Run(func(mock.Arguments) { close(fetched) })
+
+	// Close waits for the initial fetch to finish
+	sub := newTestSubscription(t, tracker, lb)
+	<-fetched
+	sub.Close()
+	ethClient.AssertExpectations(t)
+
+	_, found := tracker.LatestConfigDigest()
+	require.False(t, found)
+}
@@ -0,0 +1,58 @@
+package offchainreporting
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+)
+
+// ConfigSetParser parses a raw ConfigSet log into the contract config it sets
+type ConfigSetParser func(raw types.Log) (ocrtypes.ContractConfig, error)
+
+// WithConfigSetParser makes the tracker parse ConfigSet logs with parser
+// instead of the OffchainAggregator ABI, for forks of the contract that lay
+// out the event data differently. Logs are still routed and filtered by the
+// OffchainAggregator ConfigSet topic. Configs returned by parser are
+// validated. Digest verification can only recompute the digest of logs that
+// keep the OffchainAggregator layout; for other logs it relies on the digest
+// reported by the contract, which only covers the latest config. Corrupt logs
+// are not re-fetched.
+func WithConfigSetParser(parser ConfigSetParser) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.configSetParser = parser
+	}
+}
+
+// parseConfigSet parses a ConfigSet log with the configured parser, if any,
+// or ParseOCRConfigSet
+func (oc *OCRContractConfigTracker) parseConfigSet(raw types.Log) (ocrtypes.ContractConfig, error) {
+	if oc.configSetParser == nil {
+		return ParseOCRConfigSet(raw)
+	}
+	cc, err := oc.configSetParser(raw)
+	if err != nil {
+		return cc, errors.Wrapf(err, "custom parser could not parse ConfigSet in block %d", raw.BlockNumber)
+	}
+	if err = validateContractConfig(cc); err != nil {
+		return cc, errors.Wrapf(err, "custom parser returned unusable config for ConfigSet in block %d", raw.BlockNumber)
+	}
+	return cc, nil
+}
+
+// validateContractConfig returns an error if the config could not be run by
+// libocr
+func validateContractConfig(cc ocrtypes.ContractConfig) error {
+	if cc.ConfigDigest == (ocrtypes.ConfigDigest{}) {
+		return errors.New("config digest is empty")
+	}
+	if len(cc.Signers) == 0 {
+		return errors.New("config has no signers")
+	}
+	if len(cc.Signers) != len(cc.Transmitters) {
+		return errors.Errorf("config has %d signers but %d transmitters", len(cc.Signers), len(cc.Transmitters))
+	}
+	if cc.Threshold == 0 || 3*int(cc.Threshold) >= len(cc.Signers) {
+		return errors.Errorf("threshold %d is invalid for %d oracles", cc.Threshold, len(cc.Signers))
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package offchainreporting_test
+
+import (
+	"bytes"
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/log"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OCRContractConfigTracker_WithConfigSetParser(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	signers := []common.Address{cltest.NewAddress(), cltest.NewAddress(), cltest.NewAddress(), cltest.NewAddress()}
+	transmitters := []common.Address{cltest.NewAddress(), cltest.NewAddress(), cltest.NewAddress(), cltest.NewAddress()}
+	// The fork's ConfigSet data is the digest followed by the threshold
+	var parsed int32
+	parser := func(raw types.Log) (ocrtypes.ContractConfig, error) {
+		atomic.AddInt32(&parsed, 1)
+		if len(raw.Data) != 17 {
+			return ocrtypes.ContractConfig{}, errors.Errorf("unexpected data length %d", len(raw.Data))
+		}
+		cc := ocrtypes.ContractConfig{Signers: signers, Transmitters: transmitters, Threshold: raw.Data[16], EncodedConfigVersion: 1}
+		copy(cc.ConfigDigest[:], raw.Data[:16])
+		return cc, nil
+	}
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly(), offchainreporting.WithConfigSetParser(parser))
+	newForkLog := func(blockNumber uint64, digest byte, threshold uint8) types.Log {
+		return types.Log{
+			Address:     address,
+			Topics:      []common.Hash{offchainreporting.OCRContractConfigSet},
+			Data:        append(bytes.Repeat([]byte{digest}, 16), threshold),
+			BlockNumber: blockNumber,
+			BlockHash:   cltest.NewHash(),
+			TxHash:      cltest.NewHash(),
+		}
+	}
+
+	sub := newTestSubscription(t, tracker, lb)
+	listener := sub.(log.Listener)
+
+	broadcast := newBroadcast(newForkLog(1, 0xaa, 1))
+	listener.HandleLog(broadcast, nil)
+	broadcast.AssertCalled(t, "MarkConsumed")
+	history := tracker.ConfigHistory()
+	require.Len(t, history, 1)
+	require.Equal(t, ocrtypes.ConfigDigest{0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa}, history[0].ConfigDigest)
+	require.Equal(t, signers, history[0].Signers)
+	require.Equal(t, uint8(1), history[0].Threshold)
+	require.Equal(t, int32(1), atomic.LoadInt32(&parsed))
+
+	// An unusable config is rejected
+	broadcast = new(logmocks.Broadcast)
+	broadcast.On("RawLog").Return(newForkLog(2, 0xbb, 2))
+	broadcast.On("WasAlreadyConsumed").Return(false, nil)
+	listener.HandleLog(broadcast, nil)
+	broadcast.AssertNotCalled(t, "MarkConsumed")
+	require.Len(t, tracker.ConfigHistory(), 1)
+
+	// A log in the default layout does not parse, and is not re-fetched
+	broadcast = new(logmocks.Broadcast)
+	broadcast.On("RawLog").Return(newConfigSetLog(t, address, 3, 3))
+	broadcast.On("WasAlreadyConsumed").Return(false, nil)
+	listener.HandleLog(broadcast, nil)
+	broadcast.AssertNotCalled(t, "MarkConsumed")
+	require.Len(t, tracker.ConfigHistory(), 1)
+	ethClient.AssertNotCalled(t, "TransactionReceipt", mock.Anything, mock.Anything)
+
+	ethClient.On("FilterLogs", mock.Anything, mock.Anything).Return([]types.Log{newForkLog(4, 0xcc, 1)}, nil).Once()
+	cc, err := tracker.ConfigFromLogs(context.Background(), 4)
+	require.NoError(t, err)
+	require.Equal(t, byte(0xcc), cc.ConfigDigest[0])
+	require.Equal(t, int32(4), atomic.LoadInt32(&parsed))
+
+	ethClient.AssertExpectations(t)
+}
@@ -0,0 +1,61 @@
+package offchainreporting
+
+import (
+	"encoding/json"
+
+	gethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+)
+
+// contractConfigJSON is the persisted form of a ContractConfig. Byte fields
+// are hex encoded, and the encoded config is a pointer so that a nil config
+// round-trips as nil rather than as an empty slice.
+type contractConfigJSON struct {
+	ConfigDigest         hexutil.Bytes        `json:"configDigest"`
+	Signers              []gethCommon.Address `json:"signers"`
+	Transmitters         []gethCommon.Address `json:"transmitters"`
+	Threshold            uint8                `json:"threshold"`
+	EncodedConfigVersion hexutil.Uint64       `json:"encodedConfigVersion"`
+	Encoded              *hexutil.Bytes       `json:"encoded"`
+}
+
+// MarshalContractConfig encodes a contract config as stable JSON, for
+// persistence. UnmarshalContractConfig decodes it back exactly.
+func MarshalContractConfig(c ocrtypes.ContractConfig) ([]byte, error) {
+	cj := contractConfigJSON{
+		ConfigDigest:         c.ConfigDigest[:],
+		Signers:              c.Signers,
+		Transmitters:         c.Transmitters,
+		Threshold:            c.Threshold,
+		EncodedConfigVersion: hexutil.Uint64(c.EncodedConfigVersion),
+	}
+	if c.Encoded != nil {
+		encoded := hexutil.Bytes(c.Encoded)
+		cj.Encoded = &encoded
+	}
+	b, err := json.Marshal(cj)
+	return b, errors.Wrap(err, "could not marshal contract config")
+}
+
+// UnmarshalContractConfig decodes a contract config encoded by
+// MarshalContractConfig
+func UnmarshalContractConfig(b []byte) (c ocrtypes.ContractConfig, err error) {
+	var cj contractConfigJSON
+	if err = json.Unmarshal(b, &cj); err != nil {
+		return c, errors.Wrap(err, "could not unmarshal contract config")
+	}
+	if len(cj.ConfigDigest) != len(c.ConfigDigest) {
+		return c, errors.Errorf("config digest has %d bytes, expected %d", len(cj.ConfigDigest), len(c.ConfigDigest))
+	}
+	copy(c.ConfigDigest[:], cj.ConfigDigest)
+	c.Signers = cj.Signers
+	c.Transmitters = cj.Transmitters
+	c.Threshold = cj.Threshold
+	c.EncodedConfigVersion = uint64(cj.EncodedConfigVersion)
+	if cj.Encoded != nil {
+		c.Encoded = *cj.Encoded
+	}
+	return c, nil
+}
@@ -0,0 +1,45 @@
+package offchainreporting_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MarshalContractConfig_RoundTrip(t *testing.T) {
+	configs := []ocrtypes.ContractConfig{
+		{
+			ConfigDigest:         ocrtypes.ConfigDigest{0x00, 0x01, 0xfe, 0xff, 0x10},
+			Signers:              []common.Address{cltest.NewAddress(), cltest.NewAddress()},
+			Transmitters:         []common.Address{cltest.NewAddress(), cltest.NewAddress()},
+			Threshold:            1,
+			EncodedConfigVersion: 987654,
+			Encoded:              []byte{0x00, 0x01, 0x02, 0xff},
+		},
+		{
+			Signers:      []common.Address{},
+			Transmitters: []common.Address{},
+			Encoded:      []byte{},
+		},
+		{},
+	}
+	for _, c := range configs {
+		b, err := offchainreporting.MarshalContractConfig(c)
+		require.NoError(t, err)
+		decoded, err := offchainreporting.UnmarshalContractConfig(b)
+		require.NoError(t, err)
+		require.Equal(t, c, decoded)
+
+		// Stable across encodings
+		b2, err := offchainreporting.MarshalContractConfig(decoded)
+		require.NoError(t, err)
+		require.Equal(t, b, b2)
+	}
+
+	_, err := offchainreporting.UnmarshalContractConfig([]byte(`{"configDigest":"0x0102"}`))
+	require.Error(t, err)
+}
@@ -1,13 +1,19 @@
 package offchainreporting
 
 import (
+	"context"
+	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	gethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
 
 	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services/eth"
 	"github.com/smartcontractkit/chainlink/core/services/log"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/utils"
@@ -21,7 +27,11 @@ import (
 )
 
 var (
-	OCRContractConfigSet = getConfigSetHash()
+	OCRContractConfigSet       = getConfigSetHash()
+	OCRContractRoundRequested  = offchainAggregatorABI.Events["RoundRequested"].ID
+	OCRContractNewTransmission = offchainAggregatorABI.Events["NewTransmission"].ID
+
+	offchainAggregatorABI = eth.MustGetABI(offchainaggregator.OffchainAggregatorABI)
 )
 
 var (
@@ -29,19 +39,48 @@ var (
 	_ log.Listener                        = &OCRContractConfigSubscription{}
 )
 
+// OCRTrackedTopics returns the topics of the events handled by the tracker's
+// subscriptions. Logs with other topics are consumed without being handled.
+func OCRTrackedTopics() []gethCommon.Hash {
+	return []gethCommon.Hash{OCRContractConfigSet, OCRContractRoundRequested, OCRContractNewTransmission}
+}
+
+// OCRContractConfigSubscriptionHandleLogTimeout is the default time to wait
+// for libocr to receive a config before retrying, see WithDeliveryTimeout
 const OCRContractConfigSubscriptionHandleLogTimeout = 5 * time.Second
 
+// OCRContractMaxLogDataSize is the default size in bytes above which log
+// data is not parsed, see WithMaxLogDataSize. It is far larger than any
+// ConfigSet log the OffchainAggregator can emit.
+const OCRContractMaxLogDataSize = 1 << 20
+
+// MaxPausedBroadcasts is the number of logs buffered while the tracker is
+// paused before the oldest are dropped
+const MaxPausedBroadcasts = 1000
+
 type OCRContractConfigSubscription struct {
 	logger            logger.Logger
 	contract          *offchain_aggregator_wrapper.OffchainAggregator
 	ch                chan ocrtypes.ContractConfig
 	chIncoming        chan ocrtypes.ContractConfig
 	processLogsWorker utils.SleeperTask
-	queue             []ocrtypes.ContractConfig
+	queue             []ConfigWithBlock
 	queueMu           sync.Mutex
 	oc                *OCRContractConfigTracker
 	closer            sync.Once
 	chStop            chan struct{}
+	wg                sync.WaitGroup
+	closed            uint32
+	// closedMu serializes setting closed with goTracked, so that no goroutine
+	// is added to wg once Close is waiting on it
+	closedMu sync.Mutex
+	chNewer  chan struct{}
+	// lastDelivered is only accessed by the processLogs worker
+	lastDelivered time.Time
+	// lastHandledBlock is the highest block number of a handled log
+	lastHandledBlock uint64
+	// driftSince is only accessed by the config drift check
+	driftSince time.Time
 }
 
 func (sub *OCRContractConfigSubscription) start() {
@@ -50,77 +89,663 @@ func (sub *OCRContractConfigSubscription) start() {
 	)
 }
 
+// stopWorker stops the processLogs worker, if it was started
+func (sub *OCRContractConfigSubscription) stopWorker() {
+	if sub.processLogsWorker == nil {
+		return
+	}
+	if err := sub.processLogsWorker.Stop(); err != nil {
+		sub.logger.Error(err)
+	}
+}
+
+// goTracked runs fn in a goroutine that Close waits for. It does nothing and
+// returns false if the subscription has been closed.
+func (sub *OCRContractConfigSubscription) goTracked(fn func()) bool {
+	sub.closedMu.Lock()
+	defer sub.closedMu.Unlock()
+	if atomic.LoadUint32(&sub.closed) == 1 {
+		return false
+	}
+	sub.wg.Add(1)
+	go func() {
+		defer sub.wg.Done()
+		fn()
+	}()
+	return true
+}
+
 func (sub *OCRContractConfigSubscription) processLogs() {
+	for {
+		cc, exists := sub.dequeue()
+		if !exists {
+			return
+		}
+		if delay := sub.deliveryDelay(); delay > 0 {
+			// Hold the config until the minimum delivery interval has
+			// elapsed, delivering the newest config to arrive meanwhile
+			select {
+			case <-sub.oc.clock.After(delay):
+			case <-sub.chStop:
+				return
+			}
+			if newer, exists := sub.dequeue(); exists {
+				cc = newer
+			}
+		}
+		switch sub.deliver(cc.ContractConfig) {
+		case delivered:
+			sub.lastDelivered = sub.oc.clock.Now()
+			sub.persistConfig(cc)
+		case deliveryStopped:
+			return
+		case deliveryTimedOut:
+			// Keep trying unless a newer config has arrived in the meantime
+			sub.requeue(cc)
+		}
+	}
+}
+
+// deliveryDelay returns how long to wait before the next delivery to respect
+// the minimum delivery interval, if any
+func (sub *OCRContractConfigSubscription) deliveryDelay() time.Duration {
+	if sub.oc.minDeliveryInterval <= 0 || sub.lastDelivered.IsZero() {
+		return 0
+	}
+	return sub.lastDelivered.Add(sub.oc.minDeliveryInterval).Sub(sub.oc.clock.Now())
+}
+
+// dequeue pops the pending config, if any. The lock is not held while
+// delivering so that newer configs can replace a pending one in the meantime.
+func (sub *OCRContractConfigSubscription) dequeue() (cc ConfigWithBlock, exists bool) {
 	sub.queueMu.Lock()
 	defer sub.queueMu.Unlock()
-	for len(sub.queue) > 0 {
-		cc := sub.queue[0]
-		sub.queue = sub.queue[1:]
-
-		select {
-		// NOTE: This is thread-safe because HandleLog cannot be called concurrently with Unregister due to the design of LogBroadcaster
-		// It will never send on closed channel
-		case sub.ch <- cc:
-		case <-time.After(OCRContractConfigSubscriptionHandleLogTimeout):
-			sub.logger.Error("OCRContractConfigSubscription HandleLog timed out waiting on receive channel")
-		case <-sub.chStop:
-			return
+	if len(sub.queue) == 0 {
+		return cc, false
+	}
+	cc = sub.queue[0]
+	sub.queue = sub.queue[1:]
+	// Any pending signal refers to the config just dequeued
+	select {
+	case <-sub.chNewer:
+	default:
+	}
+	return cc, true
+}
+
+// requeue puts back a config whose delivery timed out, unless it has been
+// superseded
+func (sub *OCRContractConfigSubscription) requeue(cc ConfigWithBlock) {
+	sub.queueMu.Lock()
+	defer sub.queueMu.Unlock()
+	if len(sub.queue) == 0 {
+		sub.queue = append(sub.queue, cc)
+	}
+}
+
+// persistConfig writes the applied config to the tracker's config history
+// store, if any, giving up if the subscription is closed
+func (sub *OCRContractConfigSubscription) persistConfig(cc ConfigWithBlock) {
+	ctx, cancel := utils.ContextFromChan(sub.chStop)
+	defer cancel()
+	sub.oc.persistConfig(ctx, cc.ContractConfig, cc.BlockNumber)
+}
+
+type deliveryResult int
+
+const (
+	delivered deliveryResult = iota
+	deliveryTimedOut
+	deliverySuperseded
+	deliveryStopped
+)
+
+// deliver sends the config to the consumer. It gives up if the consumer does
+// not receive it within the delivery timeout, if a newer config is queued, or
+// if the subscription is stopped.
+func (sub *OCRContractConfigSubscription) deliver(cc ocrtypes.ContractConfig) deliveryResult {
+	_, span := sub.oc.startSpan(context.Background(), "DeliverConfig")
+	var err error
+	defer func() { endSpan(span, err) }()
+
+	select {
+	// NOTE: This is thread-safe because HandleLog cannot be called concurrently with Unregister due to the design of LogBroadcaster
+	// It will never send on closed channel
+	case sub.ch <- cc:
+		sub.oc.markConfigApplied(cc.ConfigDigest)
+		return delivered
+	case <-sub.oc.clock.After(sub.oc.deliveryTimeout):
+		sub.logger.Warnw("OCRContractConfigSubscription timed out waiting on receive channel, will retry", "timeout", sub.oc.deliveryTimeout)
+		err = errors.Errorf("consumer did not receive config within %s", sub.oc.deliveryTimeout)
+		return deliveryTimedOut
+	case <-sub.chNewer:
+		return deliverySuperseded
+	case <-sub.chStop:
+		return deliveryStopped
+	}
+}
+
+// OnConnect complies with LogListener interface. A reconnection may have
+// coincided with a reorg, so the config is re-queried if the block it was
+// set in is no longer canonical.
+func (sub *OCRContractConfigSubscription) OnConnect() {
+	sub.oc.setConnected(true)
+	sub.goTracked(sub.refreshConfigIfReorged)
+}
+
+func (sub *OCRContractConfigSubscription) refreshConfigIfReorged() {
+	ctx, cancel := utils.ContextFromChan(sub.chStop)
+	defer cancel()
+
+	canonical, err := sub.oc.configStillCanonical(ctx)
+	if err != nil {
+		sub.logger.Errorw("OCRContract: could not determine if config is still canonical", "err", err)
+		return
+	} else if canonical {
+		return
+	}
+
+	sub.logger.Warn("OCRContract: block containing the latest config is no longer canonical, re-querying config")
+	changedInBlock, _, err := sub.oc.LatestConfigDetails(ctx)
+	if err != nil {
+		sub.logger.Errorw("OCRContract: could not re-query config after reorg", "err", err)
+		return
+	}
+	cc, raw, err := sub.oc.configFromLogs(ctx, changedInBlock)
+	if err != nil {
+		sub.logger.Errorw("OCRContract: could not re-query config after reorg", "err", err)
+		return
+	}
+	// The config may now have been set in an earlier block than the one
+	// reorged out
+	sub.oc.replaceLatestConfig(cc, raw)
+
+	if !sub.oc.allowedByPolicy(cc, raw.BlockNumber) {
+		return
+	}
+	if !sub.enqueue(cc, raw.BlockNumber) {
+		sub.logger.Warnw("OCRContract: subscription closed, dropping config re-queried after reorg", "configDigest", cc.ConfigDigest, "blockNumber", raw.BlockNumber)
+	}
+}
+
+// catchUp delivers the latest config set between the later of the last config
+// seen and safeDepth blocks behind the head, if any
+func (sub *OCRContractConfigSubscription) catchUp(ctx context.Context, safeDepth uint64) error {
+	head, details, err := sub.oc.refreshHeadAndConfig(ctx)
+	if err != nil {
+		return err
+	}
+	var fromBlock uint64
+	if head > safeDepth {
+		fromBlock = head - safeDepth
+	}
+	if raw := sub.oc.getLatestConfigLog(); raw != nil && raw.BlockNumber+1 > fromBlock {
+		fromBlock = raw.BlockNumber + 1
+	}
+	if fromBlock > head || details.changedInBlock < fromBlock {
+		// The config has not changed in the range, no need to scan it
+		return nil
+	}
+
+	latest, found, err := sub.latestConfigBetween(ctx, fromBlock, head)
+	if err != nil || !found {
+		return err
+	}
+	sub.logger.Infow("OCRContract: caught up on config set while unsubscribed", "blockNumber", latest.BlockNumber)
+	sub.enqueue(latest.ContractConfig, latest.BlockNumber)
+	return nil
+}
+
+// initialFetch runs the catch-up scan and eager initial fetch, if enabled,
+// after the tracker's startup delay. It gives up if the subscription is
+// closed.
+func (sub *OCRContractConfigSubscription) initialFetch() {
+	if sub.oc.catchUpDepth == 0 && !sub.oc.eagerInitialFetch {
+		return
+	}
+	ctx, cancel := utils.ContextFromChan(sub.chStop)
+	defer cancel()
+	if err := sub.oc.waitStartupDelay(ctx); err != nil {
+		sub.logger.Warnw("OCRContract: skipping initial fetch, subscription closed during startup delay", "err", err)
+		return
+	}
+	if sub.oc.catchUpDepth > 0 {
+		if err := sub.catchUp(ctx, sub.oc.catchUpDepth); err != nil {
+			sub.logger.Warnw("OCRContract: could not catch up on missed configs", "err", err)
+		}
+	}
+	if sub.oc.eagerInitialFetch {
+		if err := sub.fetchInitialConfig(ctx); err != nil {
+			sub.logger.Warnw("OCRContract: could not fetch initial config", "err", err)
 		}
 	}
 }
 
-// OnConnect complies with LogListener interface
-func (sub *OCRContractConfigSubscription) OnConnect() {}
+// ReplayFromBlock re-scans the contract's logs from the given block up to the
+// head and queues the latest config found, if it is newer than the latest
+// config seen. It recovers configs from logs dropped by the log broadcaster.
+func (sub *OCRContractConfigSubscription) ReplayFromBlock(ctx context.Context, fromBlock uint64) error {
+	head, err := sub.oc.LatestBlockHeight(ctx)
+	if err != nil {
+		return sub.oc.wrapErr(err, "could not fetch head to replay to")
+	}
+	if raw := sub.oc.getLatestConfigLog(); raw != nil && raw.BlockNumber+1 > fromBlock {
+		fromBlock = raw.BlockNumber + 1
+	}
+	if fromBlock > head {
+		return nil
+	}
+	latest, found, err := sub.latestConfigBetween(ctx, fromBlock, head)
+	if err != nil || !found {
+		return err
+	}
+	sub.logger.Infow("OCRContract: replayed config set missed by log broadcaster", "fromBlock", fromBlock, "blockNumber", latest.BlockNumber)
+	sub.enqueue(latest.ContractConfig, latest.BlockNumber)
+	return nil
+}
+
+// latestConfigBetween returns the latest config set in the block range,
+// inclusive, if there is one and it passes the config policy
+func (sub *OCRContractConfigSubscription) latestConfigBetween(ctx context.Context, fromBlock, toBlock uint64) (latest ConfigWithBlock, found bool, err error) {
+	configs, err := sub.oc.configsBetween(ctx, fromBlock, toBlock)
+	if err != nil || len(configs) == 0 {
+		return latest, false, err
+	}
+	latest = configs[len(configs)-1]
+	return latest, sub.oc.allowedByPolicy(latest.ContractConfig, latest.BlockNumber), nil
+}
+
+// checkBlockGap warns if more than the block gap threshold of blocks were
+// skipped since the previously handled log, which suggests that logs were
+// dropped, and replays from the first skipped block if enabled
+func (sub *OCRContractConfigSubscription) checkBlockGap(blockNumber uint64) {
+	prev := atomic.LoadUint64(&sub.lastHandledBlock)
+	if blockNumber <= prev {
+		return
+	}
+	atomic.StoreUint64(&sub.lastHandledBlock, blockNumber)
+	if sub.oc.blockGapThreshold == 0 || prev == 0 || blockNumber-prev-1 <= sub.oc.blockGapThreshold {
+		return
+	}
+
+	sub.logger.Warnw("OCRContract: gap in block numbers of handled logs, logs may have been dropped", "previousBlockNumber", prev, "blockNumber", blockNumber)
+	sub.oc.metrics.IncCounter(MetricBlockGaps, map[string]string{"contract_address": sub.contract.Address().Hex()})
+	if !sub.oc.replayOnBlockGap {
+		return
+	}
+	sub.goTracked(func() {
+		ctx, cancel := utils.ContextFromChan(sub.chStop)
+		defer cancel()
+		if err := sub.ReplayFromBlock(ctx, prev+1); err != nil {
+			sub.logger.Errorw("OCRContract: could not replay logs after block gap", "err", err, "fromBlock", prev+1)
+		}
+	})
+}
+
+// fetchInitialConfig queues the contract's current config, if it has one
+func (sub *OCRContractConfigSubscription) fetchInitialConfig(ctx context.Context) error {
+	changedInBlock, _, err := sub.oc.LatestConfigDetails(ctx)
+	if err != nil {
+		return err
+	}
+	if changedInBlock == 0 {
+		// The contract has never been configured
+		return nil
+	}
+	cc, err := sub.oc.ConfigFromLogs(ctx, changedInBlock)
+	if errors.Cause(err) == ErrConfigRejected {
+		// Already logged by the policy check
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	sub.enqueue(cc, changedInBlock)
+	return nil
+}
+
+// enqueue queues the config for delivery, or records it in the tracker's
+// history if it is read-only. It returns false if the subscription has been
+// closed.
+//
+// Only the latest config matters to libocr, so a pending config that has not
+// yet been picked up for delivery is replaced rather than queued behind.
+func (sub *OCRContractConfigSubscription) enqueue(cc ocrtypes.ContractConfig, blockNumber uint64) bool {
+	sub.oc.notifyConfigListeners(cc)
+	if sub.oc.readOnly {
+		sub.oc.recordHistory(cc, blockNumber)
+		sub.oc.markConfigApplied(cc.ConfigDigest)
+		sub.persistConfig(ConfigWithBlock{cc, blockNumber})
+		return true
+	}
+
+	if sub.oc.syncDelivery {
+		return sub.deliverSync(ConfigWithBlock{cc, blockNumber})
+	}
+
+	sub.queueMu.Lock()
+	defer sub.queueMu.Unlock()
+	select {
+	case <-sub.chStop:
+		return false
+	default:
+	}
+	if len(sub.queue) > 0 {
+		sub.logger.Debugw("OCRContract: dropping stale configs in favour of newer config", "dropped", len(sub.queue))
+	}
+	sub.queue = append(sub.queue[:0], ConfigWithBlock{cc, blockNumber})
+	select {
+	case sub.chNewer <- struct{}{}:
+	default:
+	}
+	sub.processLogsWorker.WakeUp()
+	return true
+}
+
+// deliverSync puts the config on the Configs channel, replacing any config
+// libocr has not yet received, see WithSynchronousDelivery
+func (sub *OCRContractConfigSubscription) deliverSync(cc ConfigWithBlock) bool {
+	sub.queueMu.Lock()
+	select {
+	case <-sub.chStop:
+		sub.queueMu.Unlock()
+		return false
+	default:
+	}
+	select {
+	case stale := <-sub.ch:
+		sub.logger.Debugw("OCRContract: dropping stale config in favour of newer config", "configDigest", stale.ConfigDigest)
+	default:
+	}
+	// The channel is now empty and only written to under queueMu
+	sub.ch <- cc.ContractConfig
+	sub.queueMu.Unlock()
+
+	sub.oc.markConfigApplied(cc.ConfigDigest)
+	sub.persistConfig(cc)
+	return true
+}
 
 // OnDisconnect complies with LogListener interface
-func (sub *OCRContractConfigSubscription) OnDisconnect() {}
+func (sub *OCRContractConfigSubscription) OnDisconnect() {
+	sub.oc.setConnected(false)
+}
 
 // HandleLog complies with LogListener interface
 func (sub *OCRContractConfigSubscription) HandleLog(lb log.Broadcast, err error) {
+	// The broadcaster may still deliver logs until Unregister returns; the
+	// log is left unconsumed for whoever subscribes next
+	if atomic.LoadUint32(&sub.closed) == 1 {
+		return
+	}
+	if lb != nil {
+		sub.observe(lb)
+	}
 	if err != nil {
 		sub.logger.Errorw("OCRContract: error in previous LogListener", "err", err)
 		return
 	}
-
-	was, err := lb.WasAlreadyConsumed()
-	if err != nil {
-		sub.logger.Errorw("OCRContract: could not determine if log was already consumed", "error", err)
+	if sub.filteredOut(lb) {
+		return
+	}
+	if sub.oversized(lb.RawLog()) {
 		return
-	} else if was {
+	}
+	if sub.oc.bufferIfPaused(sub, lb) {
 		return
 	}
+	sub.handleLog(lb)
+}
+
+// oversized reports whether the log's data exceeds the tracker's limit, in
+// which case the log is skipped without being parsed or consumed
+func (sub *OCRContractConfigSubscription) oversized(raw types.Log) bool {
+	if sub.oc.maxLogDataSize <= 0 || len(raw.Data) <= sub.oc.maxLogDataSize {
+		return false
+	}
+	sub.logger.Warnw("OCRContract: skipping log with oversized data", "size", len(raw.Data), "limit", sub.oc.maxLogDataSize, "blockNumber", raw.BlockNumber, "txHash", raw.TxHash.Hex())
+	sub.oc.metrics.IncCounter(MetricOversizedLogs, map[string]string{"contract_address": sub.contract.Address().Hex()})
+	return true
+}
+
+// filteredOut marks the log consumed without handling it if the tracker's
+// log filter rejects it
+func (sub *OCRContractConfigSubscription) filteredOut(lb log.Broadcast) bool {
+	if sub.oc.logFilter == nil || sub.oc.logFilter(lb.RawLog()) {
+		return false
+	}
+	sub.logger.Debugw("OCRContract: skipping log rejected by filter", "blockNumber", lb.RawLog().BlockNumber, "txHash", lb.RawLog().TxHash.Hex())
+	if sub.oc.trustBroadcasterDelivery {
+		return true
+	}
+	if err := lb.MarkConsumed(); err != nil {
+		sub.logger.Errorw("OCRContract: could not mark log consumed", "error", err)
+	}
+	return true
+}
+
+// handleLogs handles the broadcasts in order, exactly as if each had been
+// passed to HandleLog. log.Broadcast has no batch API, so consumption is
+// still checked and marked one log at a time.
+func (sub *OCRContractConfigSubscription) handleLogs(broadcasts []log.Broadcast) {
+	for _, lb := range broadcasts {
+		sub.HandleLog(lb, nil)
+	}
+}
+
+// observe passes the broadcast to the debug observer, if any, making sure a
+// misbehaving observer cannot break log handling
+func (sub *OCRContractConfigSubscription) observe(lb log.Broadcast) {
+	if sub.oc.broadcastObserver == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			sub.logger.Errorw("OCRContract: broadcast observer panicked", "panic", r)
+		}
+	}()
+	sub.oc.broadcastObserver(lb)
+}
+
+func (sub *OCRContractConfigSubscription) handleLog(lb log.Broadcast) {
+	if !sub.oc.trustBroadcasterDelivery {
+		was, err := lb.WasAlreadyConsumed()
+		if err != nil {
+			sub.logger.Errorw("OCRContract: could not determine if log was already consumed", "error", err)
+			return
+		} else if was {
+			return
+		}
+	}
+	sub.oc.recordHandledLog(lb.RawLog().BlockNumber)
+	sub.checkBlockGap(lb.RawLog().BlockNumber)
 
 	topics := lb.RawLog().Topics
 	if len(topics) == 0 {
 		return
 	}
-	switch topics[0] {
+	var handled bool
+	if lb.RawLog().Removed {
+		handled = sub.handleRemoved(lb.RawLog())
+	} else {
+		handled = sub.handleTopic(lb.RawLog())
+	}
+	if !handled {
+		// Leave the log unconsumed so that it can be retried
+		return
+	}
+
+	if sub.oc.trustBroadcasterDelivery {
+		return
+	}
+	if err := lb.MarkConsumed(); err != nil {
+		sub.logger.Errorw("OCRContract: could not mark log consumed", "error", err)
+		return
+	}
+}
+
+// handleTopic handles the log according to its event, returning false if
+// the log should not be marked consumed
+func (sub *OCRContractConfigSubscription) handleTopic(raw types.Log) (handled bool) {
+	// Only the event signature in topics[0] drives routing. Later topics hold
+	// indexed arguments, so a tracked signature appearing among them is
+	// ignored here, and the parser rejects the log for its topic count.
+	event := raw.Topics[0]
+	switch event {
 	case OCRContractConfigSet:
-		raw := lb.RawLog()
-		if raw.Address != sub.contract.Address() {
-			sub.logger.Errorf("log address of 0x%x does not match configured contract address of 0x%x", raw.Address, sub.contract.Address())
-			return
+		handled = sub.handleConfigSet(raw)
+	case OCRContractRoundRequested:
+		handled = sub.handleRoundRequested(raw)
+	case OCRContractNewTransmission:
+		handled = sub.handleNewTransmission(raw)
+	default:
+		// Logs we don't track can always be consumed
+		sub.logger.Debugw("OCRContract: ignoring log with unrecognized topic", "topic", event.Hex())
+		sub.oc.metrics.IncCounter(MetricUnrecognizedLogs, map[string]string{"contract_address": sub.contract.Address().Hex(), "topic": event.Hex()})
+		handled = true
+	}
+	return handled
+}
+
+// handleRemoved notifies reorg listeners of a ConfigSet or RoundRequested log
+// removed by a reorg. Removed logs are never applied.
+func (sub *OCRContractConfigSubscription) handleRemoved(raw types.Log) bool {
+	if raw.Address != sub.contract.Address() {
+		return true
+	}
+	event := ReorgEvent{BlockNumber: raw.BlockNumber, BlockHash: raw.BlockHash}
+	switch raw.Topics[0] {
+	case OCRContractConfigSet:
+		cc, err := sub.oc.parseConfigSet(raw)
+		if err != nil {
+			sub.logger.Errorw("OCRContract: skipping malformed removed config set", "err", err)
+			return true
 		}
-		configSet, err2 := sub.oc.contractFilterer.ParseConfigSet(raw)
-		if err2 != nil {
-			sub.logger.Errorw("could not parse config set", "err", err2)
-			return
+		event.Event, event.ConfigDigest = "ConfigSet", cc.ConfigDigest
+		sub.oc.invalidateConfigDetailsCache()
+	case OCRContractRoundRequested:
+		rr, err := ParseOCRRoundRequested(raw)
+		if err != nil {
+			sub.logger.Errorw("OCRContract: skipping malformed removed round requested", "err", err)
+			return true
 		}
-		configSet.Raw = lb.RawLog()
-		cc := confighelper.ContractConfigFromConfigSetEvent(*configSet)
-
-		sub.queueMu.Lock()
-		defer sub.queueMu.Unlock()
-		sub.queue = append(sub.queue, cc)
-		sub.processLogsWorker.WakeUp()
+		event.Event, event.ConfigDigest = "RoundRequested", ocrtypes.ConfigDigest(rr.ConfigDigest)
 	default:
+		return true
 	}
+	sub.logger.Warnw("OCRContract: log removed by reorg", "event", event.Event, "configDigest", event.ConfigDigest, "blockNumber", event.BlockNumber)
+	sub.oc.notifyReorgListeners(event)
+	return true
+}
 
-	err = lb.MarkConsumed()
+// handleConfigSet parses and queues the config, returning false if the log
+// should not be marked consumed
+func (sub *OCRContractConfigSubscription) handleConfigSet(raw types.Log) bool {
+	if raw.Address != sub.contract.Address() {
+		sub.oc.addressMismatchLogger.Logw("OCRContract: log address does not match configured contract address", "logAddress", raw.Address.Hex(), "contractAddress", sub.contract.Address().Hex())
+		return false
+	}
+	cc, err := sub.oc.parseConfigSet(raw)
+	if err != nil && sub.oc.configSetParser == nil && validateEventTopic(raw, "ConfigSet") == nil && validateTopicCount(raw, "ConfigSet") == nil {
+		// The topics are well formed, so the data may have been corrupted in
+		// transit
+		sub.logger.Warnw("OCRContract: could not parse config set, re-fetching log", "err", err, "txHash", raw.TxHash.Hex(), "logIndex", raw.Index)
+		refetched, permanent, refetchErr := sub.refetchConfigSet(raw)
+		if permanent {
+			sub.oc.recordParseResult(refetchErr)
+			sub.logger.Errorw("OCRContract: config set is malformed at the node, marking consumed", "err", refetchErr)
+			return true
+		} else if refetchErr != nil {
+			sub.logger.Warnw("OCRContract: could not re-fetch config set, will retry", "err", refetchErr)
+		} else {
+			cc, raw, err = confighelper.ContractConfigFromConfigSetEvent(*refetched), refetched.Raw, nil
+		}
+	}
+	sub.oc.recordParseResult(err)
 	if err != nil {
-		sub.logger.Errorw("OCRContract: could not mark log consumed", "error", err)
-		return
+		sub.logger.Errorw("OCRContract: skipping malformed config set", "err", err)
+		return false
 	}
+	if sub.oc.verifyConfigDigest {
+		ctx, cancel := context.WithTimeout(context.Background(), OCRContractConfigSubscriptionHandleLogTimeout)
+		defer cancel()
+		if err = sub.oc.verifyConfigSet(ctx, cc, raw); err != nil {
+			sub.logger.Errorw("OCRContract: config set failed digest verification", "err", err)
+			return false
+		}
+	}
+	if !sub.oc.allowedByPolicy(cc, raw.BlockNumber) {
+		// The config will not become acceptable on retry
+		return true
+	}
+	sub.oc.setLatestConfig(cc, raw)
+	sub.oc.invalidateConfigDetailsCache()
+	return sub.enqueue(cc, raw.BlockNumber)
+}
+
+// refetchConfigSet fetches the ConfigSet log again from the receipt of its
+// transaction. permanent is true if the node serves the same unparseable log
+// again, in which case retrying is pointless.
+func (sub *OCRContractConfigSubscription) refetchConfigSet(raw types.Log) (configSet *offchainaggregator.OffchainAggregatorConfigSet, permanent bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), OCRContractConfigSubscriptionHandleLogTimeout)
+	defer cancel()
+	var receipt *types.Receipt
+	err = sub.oc.call(ctx, func() error {
+		return sub.oc.withFailover(ctx, func(e endpoint) (err2 error) {
+			receipt, err2 = e.client.TransactionReceipt(ctx, raw.TxHash)
+			return err2
+		})
+	})
+	if err != nil {
+		return nil, false, sub.oc.wrapErr(err, fmt.Sprintf("could not fetch receipt of tx 0x%x", raw.TxHash))
+	}
+	for _, fetched := range receipt.Logs {
+		if fetched.Index != raw.Index {
+			continue
+		}
+		if fetched.Address != raw.Address || fetched.BlockHash != raw.BlockHash {
+			// Most likely reorged, the broadcaster will deliver the log again
+			return nil, false, sub.oc.errorf("log %d of tx 0x%x in block 0x%x does not match the log delivered", raw.Index, raw.TxHash, fetched.BlockHash)
+		}
+		configSet, err = parseConfigSetEvent(*fetched)
+		return configSet, err != nil, err
+	}
+	return nil, false, sub.oc.errorf("tx 0x%x has no log with index %d", raw.TxHash, raw.Index)
+}
+
+// handleRoundRequested records the round request, returning false if the log
+// should not be marked consumed
+func (sub *OCRContractConfigSubscription) handleRoundRequested(raw types.Log) bool {
+	if raw.Address != sub.contract.Address() {
+		sub.oc.addressMismatchLogger.Logw("OCRContract: log address does not match configured contract address", "logAddress", raw.Address.Hex(), "contractAddress", sub.contract.Address().Hex())
+		return false
+	}
+	rr, err := ParseOCRRoundRequested(raw)
+	sub.oc.recordParseResult(err)
+	if err != nil {
+		sub.logger.Errorw("OCRContract: skipping malformed round requested", "err", err)
+		return false
+	}
+	sub.oc.recordRoundRequest(*rr)
+	sub.oc.notifyEventListeners(TrackerEvent{Type: TrackerEventRoundRequested, ConfigDigest: ocrtypes.ConfigDigest(rr.ConfigDigest), BlockNumber: raw.BlockNumber})
+	return true
+}
+
+// handleNewTransmission records the transmission for LatestTransmission,
+// returning false if the log should not be marked consumed
+func (sub *OCRContractConfigSubscription) handleNewTransmission(raw types.Log) bool {
+	if raw.Address != sub.contract.Address() {
+		sub.oc.addressMismatchLogger.Logw("OCRContract: log address does not match configured contract address", "logAddress", raw.Address.Hex(), "contractAddress", sub.contract.Address().Hex())
+		return false
+	}
+	nt, err := ParseOCRNewTransmission(raw)
+	sub.oc.recordParseResult(err)
+	if err != nil {
+		sub.logger.Errorw("OCRContract: skipping malformed new transmission", "err", err)
+		return false
+	}
+	sub.oc.recordTransmission(*nt)
+	return true
 }
 
 // IsV2Job complies with LogListener interface
@@ -146,17 +771,45 @@ func (sub *OCRContractConfigSubscription) Configs() <-chan ocrtypes.ContractConf
 // Close complies with ContractConfigSubscription interface
 func (sub *OCRContractConfigSubscription) Close() {
 	sub.closer.Do(func() {
+		sub.closedMu.Lock()
+		atomic.StoreUint32(&sub.closed, 1)
+		sub.closedMu.Unlock()
 		close(sub.chStop)
 		sub.oc.logBroadcaster.Unregister(sub.oc.contract, sub)
-		err := sub.processLogsWorker.Stop()
-		if err != nil {
-			sub.logger.Error(err)
-		}
+		sub.oc.removeSubscription(sub)
+		sub.wg.Wait()
+		sub.stopWorker()
 
-		close(sub.ch)
+		if sub.ch != nil {
+			// Synchronous delivery sends under queueMu
+			sub.queueMu.Lock()
+			close(sub.ch)
+			sub.queueMu.Unlock()
+		}
+		sub.oc.notifyEventListeners(TrackerEvent{Type: TrackerEventStopped})
 	})
 }
 
+// validateTopicCount checks that the log has exactly one topic for the event
+// signature plus one per indexed parameter of the named event, so that
+// crafted logs with extra or missing topics are never handed to the unpacker
+func validateTopicCount(raw types.Log, eventName string) error {
+	event, exists := offchainAggregatorABI.Events[eventName]
+	if !exists {
+		return errors.Errorf("unknown OffchainAggregator event %s", eventName)
+	}
+	expected := 1
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			expected++
+		}
+	}
+	if len(raw.Topics) != expected {
+		return errors.Errorf("%s log in tx 0x%x has %d topics, expected %d", eventName, raw.TxHash, len(raw.Topics), expected)
+	}
+	return nil
+}
+
 func getConfigSetHash() gethCommon.Hash {
 	abi, err := abi.JSON(strings.NewReader(offchainaggregator.OffchainAggregatorABI))
 	if err != nil {
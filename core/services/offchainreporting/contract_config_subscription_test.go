@@ -0,0 +1,678 @@
+package offchainreporting_test
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/gethwrappers/generated/offchain_aggregator_wrapper"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services/log"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	logtestutil "github.com/smartcontractkit/chainlink/core/services/log/testutil"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/libocr/gethwrappers/offchainaggregator"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func Test_OCRContractConfigSubscription_DeliveryTimeoutUsesClock(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	clock := cltest.NewTriggerClock(t)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithClock(clock))
+
+	sub := newTestSubscription(t, tracker, lb)
+	listener := sub.(log.Listener)
+
+	// Nobody reads the first config, so delivery is only retried once the
+	// clock fires
+	listener.HandleLog(newBroadcast(newConfigSetLog(t, address, 1, 1)), nil)
+	clock.Trigger()
+
+	cc := <-sub.Configs()
+	require.Equal(t, common.BigToAddress(big.NewInt(1)), cc.Signers[0])
+}
+
+func Test_OCRContractConfigSubscription_SynchronousDelivery(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithSynchronousDelivery())
+
+	sub := newTestSubscription(t, tracker, lb)
+	require.False(t, sub.(*offchainreporting.OCRContractConfigSubscription).ExportedHasWorker())
+	listener := sub.(log.Listener)
+
+	// The config is on the channel as soon as HandleLog returns
+	listener.HandleLog(newBroadcast(newConfigSetLog(t, address, 1, 1)), nil)
+	select {
+	case cc := <-sub.Configs():
+		require.Equal(t, common.BigToAddress(big.NewInt(1)), cc.Signers[0])
+	default:
+		t.Fatal("config not delivered by HandleLog")
+	}
+
+	// Configs not yet received are replaced by newer ones without blocking
+	for i := uint64(2); i <= 4; i++ {
+		listener.HandleLog(newBroadcast(newConfigSetLog(t, address, i, i)), nil)
+	}
+	select {
+	case cc := <-sub.Configs():
+		require.Equal(t, common.BigToAddress(big.NewInt(4)), cc.Signers[0])
+	default:
+		t.Fatal("config not delivered by HandleLog")
+	}
+	select {
+	case cc := <-sub.Configs():
+		t.Fatalf("unexpected config %v", cc)
+	default:
+	}
+}
+
+func Test_OCRContractConfigSubscription_MinDeliveryInterval(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	clock := newFakeClock()
+	tracker, address := newTestTracker(t, ethClient, lb,
+		offchainreporting.WithClock(clock),
+		offchainreporting.WithMinDeliveryInterval(time.Minute),
+	)
+
+	sub := newTestSubscription(t, tracker, lb)
+	listener := sub.(log.Listener)
+
+	// The first config is delivered straight away
+	listener.HandleLog(newBroadcast(newConfigSetLog(t, address, 1, 1)), nil)
+	cc := <-sub.Configs()
+	require.Equal(t, common.BigToAddress(big.NewInt(1)), cc.Signers[0])
+
+	// Configs arriving within the interval are held and coalesced
+	for i := uint64(2); i <= 4; i++ {
+		listener.HandleLog(newBroadcast(newConfigSetLog(t, address, i, i)), nil)
+	}
+	clock.awaitAfter(t, time.Minute)
+	select {
+	case cc = <-sub.Configs():
+		t.Fatalf("config delivered before the interval elapsed: %v", cc)
+	default:
+	}
+
+	clock.Advance(time.Minute)
+	cc = <-sub.Configs()
+	require.Equal(t, common.BigToAddress(big.NewInt(4)), cc.Signers[0])
+
+	// Nothing else was pending, so the next config delivered is a new one
+	listener.HandleLog(newBroadcast(newConfigSetLog(t, address, 5, 5)), nil)
+	clock.awaitAfter(t, time.Minute)
+	clock.Advance(time.Minute)
+	cc = <-sub.Configs()
+	require.Equal(t, common.BigToAddress(big.NewInt(5)), cc.Signers[0])
+}
+
+func Test_OCRContractConfigSubscription_StuckConsumerGetsNewestConfig(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	clock := newFakeClock()
+	tracker, address := newTestTracker(t, ethClient, lb,
+		offchainreporting.WithClock(clock),
+		offchainreporting.WithDeliveryTimeout(time.Second),
+	)
+
+	sub := newTestSubscription(t, tracker, lb)
+	listener := sub.(log.Listener)
+
+	// The consumer is stuck for several delivery timeouts while both configs
+	// arrive, so the first is superseded before it is ever received
+	listener.HandleLog(newBroadcast(newConfigSetLog(t, address, 1, 1)), nil)
+	clock.awaitAfter(t, time.Second)
+	clock.Advance(time.Second)
+	clock.awaitAfter(t, time.Second)
+	listener.HandleLog(newBroadcast(newConfigSetLog(t, address, 2, 2)), nil)
+	clock.awaitAfter(t, time.Second)
+	clock.Advance(time.Second)
+	clock.awaitAfter(t, time.Second)
+
+	cc := <-sub.Configs()
+	require.Equal(t, common.BigToAddress(big.NewInt(2)), cc.Signers[0])
+
+	// Nothing else was pending, so the next config delivered is a new one
+	listener.HandleLog(newBroadcast(newConfigSetLog(t, address, 3, 3)), nil)
+	cc = <-sub.Configs()
+	require.Equal(t, common.BigToAddress(big.NewInt(3)), cc.Signers[0])
+}
+
+func Test_OCRContractConfigSubscription_HandleLog_MarkConsumed(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly())
+
+	sub := newTestSubscription(t, tracker, lb)
+	listener := sub.(log.Listener)
+
+	t.Run("does not mark unparseable config consumed", func(t *testing.T) {
+		raw := newConfigSetLog(t, address, 1, 1)
+		raw.Data = raw.Data[:10]
+		ethClient.On("TransactionReceipt", mock.Anything, raw.TxHash).Return(nil, errors.New("not found")).Once()
+		broadcast := new(logmocks.Broadcast)
+		broadcast.On("RawLog").Return(raw)
+		broadcast.On("WasAlreadyConsumed").Return(false, nil)
+
+		listener.HandleLog(broadcast, nil)
+		broadcast.AssertNotCalled(t, "MarkConsumed")
+		require.Len(t, tracker.ConfigHistory(), 0)
+	})
+
+	t.Run("marks untracked logs consumed", func(t *testing.T) {
+		topic := cltest.NewHash()
+		broadcast := newBroadcast(types.Log{Address: address, Topics: []common.Hash{topic}})
+		listener.HandleLog(broadcast, nil)
+		broadcast.AssertCalled(t, "MarkConsumed")
+
+		counter := offchainreporting.PromOCRTrackerUnrecognizedLogs.WithLabelValues(address.Hex(), topic.Hex())
+		require.Equal(t, float64(1), testutil.ToFloat64(counter))
+	})
+
+	t.Run("marks handled config consumed", func(t *testing.T) {
+		broadcast := newBroadcast(newConfigSetLog(t, address, 2, 2))
+		listener.HandleLog(broadcast, nil)
+		broadcast.AssertCalled(t, "MarkConsumed")
+		require.Len(t, tracker.ConfigHistory(), 1)
+	})
+}
+
+func Test_OCRContractConfigSubscription_HandleLog_InMemoryBroadcaster(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := logtestutil.NewBroadcaster()
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly())
+
+	sub, err := tracker.SubscribeToNewConfigs(context.Background())
+	require.NoError(t, err)
+	listener := sub.(log.Listener)
+	require.Equal(t, []log.Listener{listener}, lb.Listeners(address))
+
+	raw := newConfigSetLog(t, address, 1, 1)
+	require.Equal(t, 1, lb.Broadcast(raw))
+	require.Len(t, tracker.ConfigHistory(), 1)
+	require.True(t, lb.WasConsumed(raw, listener))
+
+	// A redelivered log was already consumed and is ignored
+	require.Equal(t, 1, lb.Broadcast(raw))
+	require.Len(t, tracker.ConfigHistory(), 1)
+
+	sub.Close()
+	require.Empty(t, lb.Listeners(address))
+}
+
+func Test_OCRContractConfigSubscription_HandleLog_RejectsWrongTopicCount(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly())
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	tooMany := newConfigSetLog(t, address, 1, 1)
+	tooMany.Topics = append(tooMany.Topics, cltest.NewHash())
+	tooFew := newConfigSetLog(t, address, 1, 1)
+	tooFew.Topics = []common.Hash{}
+
+	for _, raw := range []types.Log{tooMany, tooFew} {
+		broadcast := new(logmocks.Broadcast)
+		broadcast.On("RawLog").Return(raw)
+		broadcast.On("WasAlreadyConsumed").Return(false, nil)
+
+		sub.(log.Listener).HandleLog(broadcast, nil)
+		broadcast.AssertNotCalled(t, "MarkConsumed")
+	}
+	require.Len(t, tracker.ConfigHistory(), 0)
+
+	ethClient.On("FilterLogs", mock.Anything, mock.Anything).Return([]types.Log{tooMany}, nil).Once()
+	_, err := tracker.ConfigFromLogs(context.Background(), 1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has 2 topics, expected 1")
+}
+
+func Test_OCRContractConfigSubscription_HandleLog_RoutesByFirstTopic(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	sink := &fakeMetricsSink{}
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly(), offchainreporting.WithMetricsSink(sink))
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	// Routed as a ConfigSet by topics[0], then rejected for its topic count
+	duplicated := newConfigSetLog(t, address, 1, 1)
+	duplicated.Topics = append(duplicated.Topics, offchainreporting.OCRContractConfigSet)
+	broadcast := new(logmocks.Broadcast)
+	broadcast.On("RawLog").Return(duplicated)
+	broadcast.On("WasAlreadyConsumed").Return(false, nil)
+	sub.(log.Listener).HandleLog(broadcast, nil)
+	broadcast.AssertNotCalled(t, "MarkConsumed")
+	require.Empty(t, sink.metrics)
+
+	// Routed as unrecognized by topics[0], despite the ConfigSet topic at index 1
+	unrecognized := newConfigSetLog(t, address, 2, 2)
+	topic := cltest.NewHash()
+	unrecognized.Topics = []common.Hash{topic, offchainreporting.OCRContractConfigSet}
+	sub.(log.Listener).HandleLog(newBroadcast(unrecognized), nil)
+	require.Equal(t, []fakeMetric{
+		{offchainreporting.MetricUnrecognizedLogs, 1, map[string]string{"contract_address": address.Hex(), "topic": topic.Hex()}},
+	}, sink.metrics)
+
+	require.Len(t, tracker.ConfigHistory(), 0)
+	select {
+	case cc := <-sub.Configs():
+		t.Fatalf("unexpected config %v", cc)
+	default:
+	}
+}
+
+func Test_OCRContractConfigSubscription_OnConnectAfterClose(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, _ := newTestTracker(t, ethClient, lb)
+
+	sub := newTestSubscription(t, tracker, lb)
+	sub.Close()
+
+	// Does not start a goroutine that Close is no longer waiting for
+	sub.(log.Listener).OnConnect()
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigSubscription_AddressMismatchLogging(t *testing.T) {
+	address := cltest.NewAddress()
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	contract, err := offchain_aggregator_wrapper.NewOffchainAggregator(address, ethClient)
+	require.NoError(t, err)
+	contractCaller, err := offchainaggregator.NewOffchainAggregatorCaller(address, ethClient)
+	require.NoError(t, err)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	clock := newFakeClock()
+	tracker, err := offchainreporting.NewOCRContractConfigTracker(contract, contractCaller, ethClient, lb, 42,
+		logger.Logger{SugaredLogger: zap.New(core).Sugar()},
+		offchainreporting.WithClock(clock),
+		offchainreporting.WithAddressMismatchLogging(zapcore.WarnLevel, time.Minute),
+	)
+	require.NoError(t, err)
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	mismatched := func() *logmocks.Broadcast {
+		broadcast := new(logmocks.Broadcast)
+		broadcast.On("RawLog").Return(newConfigSetLog(t, cltest.NewAddress(), 1, 1))
+		broadcast.On("WasAlreadyConsumed").Return(false, nil)
+		return broadcast
+	}
+	countMismatches := func() int {
+		return logs.FilterMessageSnippet("does not match configured contract address").Len()
+	}
+
+	for i := 0; i < 5; i++ {
+		sub.(log.Listener).HandleLog(mismatched(), nil)
+	}
+	require.Equal(t, 1, countMismatches())
+	require.Equal(t, zapcore.WarnLevel, logs.FilterMessageSnippet("does not match").All()[0].Level)
+
+	clock.Advance(time.Minute)
+	sub.(log.Listener).HandleLog(mismatched(), nil)
+	require.Equal(t, 2, countMismatches())
+}
+
+func Test_OCRContractConfigSubscription_LatestConfigWins(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb)
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	for i := uint64(1); i <= 5; i++ {
+		sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, i, i)), nil)
+	}
+
+	// The first config may already be in flight by the time the others
+	// arrive, but every config in between is superseded by the newest
+	newest := common.BigToAddress(big.NewInt(5))
+	var received []common.Address
+	for len(received) == 0 || received[len(received)-1] != newest {
+		select {
+		case cc := <-sub.Configs():
+			received = append(received, cc.Signers[0])
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for newest config, received %v", received)
+		}
+	}
+	require.LessOrEqual(t, len(received), 2)
+	if len(received) == 2 {
+		require.Equal(t, common.BigToAddress(big.NewInt(1)), received[0])
+	}
+
+	// Nothing else was pending, so the next config delivered is a new one
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 6, 6)), nil)
+	cc := <-sub.Configs()
+	require.Equal(t, common.BigToAddress(big.NewInt(6)), cc.Signers[0])
+}
+
+func Test_OCRContractConfigSubscription_LogFilter(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb,
+		offchainreporting.WithReadOnly(),
+		offchainreporting.WithLogFilter(func(raw types.Log) bool {
+			return raw.BlockNumber != 2
+		}),
+	)
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	rejected := newBroadcast(newConfigSetLog(t, address, 2, 2))
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 1, 1)), nil)
+	sub.(log.Listener).HandleLog(rejected, nil)
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 3, 3)), nil)
+
+	history := tracker.ConfigHistory()
+	require.Len(t, history, 2)
+	require.Equal(t, uint64(1), history[0].BlockNumber)
+	require.Equal(t, uint64(3), history[1].BlockNumber)
+	rejected.AssertCalled(t, "MarkConsumed")
+	rejected.AssertNotCalled(t, "WasAlreadyConsumed")
+}
+
+func Test_OCRContractConfigSubscription_BroadcastObserver(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	var observed []log.Broadcast
+	observer := func(broadcast log.Broadcast) {
+		observed = append(observed, broadcast)
+		panic("observers must not break log handling")
+	}
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly(), offchainreporting.WithBroadcastObserver(observer))
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	broadcast := newBroadcast(newConfigSetLog(t, address, 1, 1))
+	sub.(log.Listener).HandleLog(broadcast, nil)
+
+	require.Equal(t, []log.Broadcast{broadcast}, observed)
+	broadcast.AssertCalled(t, "MarkConsumed")
+	require.Len(t, tracker.ConfigHistory(), 1)
+}
+
+func Test_OCRContractConfigSubscription_HandleLogDuringClose(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb)
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	raw := newConfigSetLog(t, address, 1, 1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			sub.(log.Listener).HandleLog(newBroadcast(raw), nil)
+		}
+	}()
+	sub.Close()
+	wg.Wait()
+
+	// Once closed, logs are ignored and left unconsumed
+	broadcast := newBroadcast(raw)
+	sub.(log.Listener).HandleLog(broadcast, nil)
+	broadcast.AssertNotCalled(t, "WasAlreadyConsumed")
+	broadcast.AssertNotCalled(t, "MarkConsumed")
+}
+
+func Test_OCRContractConfigSubscription_OversizedLogData(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	sink := &fakeMetricsSink{}
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly(), offchainreporting.WithMetricsSink(sink), offchainreporting.WithMaxLogDataSize(4096))
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	oversized := newConfigSetLog(t, address, 1, 1)
+	oversized.Data = append(oversized.Data, make([]byte, 4096)...)
+	broadcast := new(logmocks.Broadcast)
+	broadcast.On("RawLog").Return(oversized)
+	sub.(log.Listener).HandleLog(broadcast, nil)
+
+	require.Len(t, tracker.ConfigHistory(), 0)
+	broadcast.AssertNotCalled(t, "WasAlreadyConsumed")
+	broadcast.AssertNotCalled(t, "MarkConsumed")
+	require.Equal(t, []fakeMetric{
+		{offchainreporting.MetricOversizedLogs, 1, map[string]string{"contract_address": address.Hex()}},
+	}, sink.metrics)
+
+	// Logs within the limit are handled as usual
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 2, 2)), nil)
+	require.Len(t, tracker.ConfigHistory(), 1)
+}
+
+func Test_OCRContractConfigSubscription_RefetchesCorruptConfigSet(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly())
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	t.Run("applies the re-fetched config", func(t *testing.T) {
+		valid := newConfigSetLog(t, address, 1, 1)
+		valid.Index = 3
+		corrupt := valid
+		corrupt.Data = valid.Data[:len(valid.Data)-5]
+		ethClient.On("TransactionReceipt", mock.Anything, valid.TxHash).Return(&types.Receipt{Logs: []*types.Log{&valid}}, nil).Once()
+
+		broadcast := newBroadcast(corrupt)
+		sub.(log.Listener).HandleLog(broadcast, nil)
+		broadcast.AssertCalled(t, "MarkConsumed")
+		history := tracker.ConfigHistory()
+		require.Len(t, history, 1)
+		require.Equal(t, common.BigToAddress(big.NewInt(1)), history[0].Signers[0])
+	})
+
+	t.Run("marks consumed if the node serves the same corrupt log", func(t *testing.T) {
+		corrupt := newConfigSetLog(t, address, 2, 2)
+		corrupt.Data = corrupt.Data[:10]
+		ethClient.On("TransactionReceipt", mock.Anything, corrupt.TxHash).Return(&types.Receipt{Logs: []*types.Log{&corrupt}}, nil).Once()
+
+		broadcast := newBroadcast(corrupt)
+		sub.(log.Listener).HandleLog(broadcast, nil)
+		broadcast.AssertCalled(t, "MarkConsumed")
+		require.Len(t, tracker.ConfigHistory(), 1)
+	})
+
+	t.Run("retries if the log cannot be re-fetched", func(t *testing.T) {
+		corrupt := newConfigSetLog(t, address, 3, 3)
+		corrupt.Data = corrupt.Data[:10]
+		ethClient.On("TransactionReceipt", mock.Anything, corrupt.TxHash).Return(nil, errors.New("connection refused")).Once()
+
+		broadcast := new(logmocks.Broadcast)
+		broadcast.On("RawLog").Return(corrupt)
+		broadcast.On("WasAlreadyConsumed").Return(false, nil)
+		sub.(log.Listener).HandleLog(broadcast, nil)
+		broadcast.AssertNotCalled(t, "MarkConsumed")
+		require.Len(t, tracker.ConfigHistory(), 1)
+	})
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigSubscription_RejectsMisroutedLog(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly())
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	roundRequested := newRoundRequestedLog(t, address, 1, ocrtypes.ConfigDigest{1}, 1, 1)
+	require.False(t, sub.(*offchainreporting.OCRContractConfigSubscription).ExportedHandleConfigSet(roundRequested))
+	require.Len(t, tracker.ConfigHistory(), 0)
+
+	_, err := offchainreporting.ParseOCRConfigSet(roundRequested)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has topic")
+	_, err = offchainreporting.ParseOCRRoundRequested(newConfigSetLog(t, address, 1, 1))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has topic")
+}
+
+func Test_OCRContractConfigSubscription_BlockGapDetection(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	sink := &fakeMetricsSink{}
+	tracker, address := newTestTracker(t, ethClient, lb,
+		offchainreporting.WithBlockGapDetection(2, true),
+		offchainreporting.WithMetricsSink(sink),
+	)
+
+	sub := newTestSubscription(t, tracker, lb)
+	listener := sub.(log.Listener)
+
+	// A config was set in block 104 but its log was dropped
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(&models.Head{Number: 110}, nil).Once()
+	ethClient.On("FilterLogs", mock.Anything, mock.MatchedBy(func(q ethereum.FilterQuery) bool {
+		return q.FromBlock.Int64() == 103 && q.ToBlock.Int64() == 110
+	})).Return([]types.Log{newConfigSetLog(t, address, 104, 4)}, nil).Once()
+
+	topic := cltest.NewHash()
+	for _, blockNumber := range []uint64{100, 102, 106} {
+		listener.HandleLog(newBroadcast(types.Log{Address: address, Topics: []common.Hash{topic}, BlockNumber: blockNumber}), nil)
+	}
+
+	select {
+	case cc := <-sub.Configs():
+		require.Equal(t, common.BigToAddress(big.NewInt(4)), cc.Signers[0])
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for replayed config")
+	}
+	ethClient.AssertExpectations(t)
+
+	var gaps int
+	sink.mu.Lock()
+	for _, m := range sink.metrics {
+		if m.name == offchainreporting.MetricBlockGaps {
+			gaps++
+		}
+	}
+	sink.mu.Unlock()
+	require.Equal(t, 1, gaps)
+}
+
+func Test_OCRContractConfigSubscription_TrustBroadcasterDelivery(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly(), offchainreporting.WithTrustBroadcasterDelivery())
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	broadcast := new(logmocks.Broadcast)
+	broadcast.On("RawLog").Return(newConfigSetLog(t, address, 1, 1))
+	sub.(log.Listener).HandleLog(broadcast, nil)
+
+	require.Len(t, tracker.ConfigHistory(), 1)
+	broadcast.AssertNotCalled(t, "WasAlreadyConsumed")
+	broadcast.AssertNotCalled(t, "MarkConsumed")
+}
+
+// dbBroadcast simulates a broadcast whose consumption is tracked in the
+// database, with a fixed round trip per call
+type dbBroadcast struct {
+	log.Broadcast
+	raw types.Log
+}
+
+func (b dbBroadcast) RawLog() types.Log { return b.raw }
+func (b dbBroadcast) WasAlreadyConsumed() (bool, error) {
+	time.Sleep(100 * time.Microsecond)
+	return false, nil
+}
+func (b dbBroadcast) MarkConsumed() error {
+	time.Sleep(100 * time.Microsecond)
+	return nil
+}
+
+func BenchmarkOCRContractConfigSubscription_HandleLog(b *testing.B) {
+	for _, test := range []struct {
+		name string
+		opts []offchainreporting.OCRContractConfigTrackerOption
+	}{
+		{"consumed check", nil},
+		{"trusted delivery", []offchainreporting.OCRContractConfigTrackerOption{offchainreporting.WithTrustBroadcasterDelivery()}},
+	} {
+		b.Run(test.name, func(b *testing.B) {
+			lb := new(logmocks.Broadcaster)
+			opts := append([]offchainreporting.OCRContractConfigTrackerOption{offchainreporting.WithReadOnly()}, test.opts...)
+			tracker, address := newTestTracker(b, new(mocks.Client), lb, opts...)
+			sub := newTestSubscription(b, tracker, lb)
+			listener := sub.(log.Listener)
+			broadcast := dbBroadcast{raw: types.Log{Address: address, Topics: []common.Hash{cltest.NewHash()}}}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				listener.HandleLog(broadcast, nil)
+			}
+		})
+	}
+}
+
+func Test_OCRContractConfigSubscription_HandleLogs(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly())
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	var broadcasts []log.Broadcast
+	for i := uint64(1); i <= 3; i++ {
+		broadcasts = append(broadcasts, newBroadcast(newConfigSetLog(t, address, i, i)))
+	}
+	sub.(*offchainreporting.OCRContractConfigSubscription).ExportedHandleLogs(broadcasts)
+
+	history := tracker.ConfigHistory()
+	require.Len(t, history, 3)
+	for i, cc := range history {
+		require.Equal(t, uint64(i+1), cc.BlockNumber)
+		broadcasts[i].(*logmocks.Broadcast).AssertCalled(t, "MarkConsumed")
+	}
+}
+
+func BenchmarkOCRContractConfigSubscription_HandleLogs(b *testing.B) {
+	const batchSize = 100
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(b, new(mocks.Client), lb, offchainreporting.WithReadOnly())
+	sub := newTestSubscription(b, tracker, lb)
+
+	broadcasts := make([]log.Broadcast, batchSize)
+	for i := range broadcasts {
+		broadcasts[i] = dbBroadcast{raw: types.Log{Address: address, Topics: []common.Hash{cltest.NewHash()}, BlockNumber: uint64(i)}}
+	}
+
+	b.Run("one at a time", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, broadcast := range broadcasts {
+				sub.(log.Listener).HandleLog(broadcast, nil)
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sub.(*offchainreporting.OCRContractConfigSubscription).ExportedHandleLogs(broadcasts)
+		}
+	})
+}
@@ -2,20 +2,33 @@ package offchainreporting
 
 import (
 	"context"
+	"encoding/binary"
+	"fmt"
 	"math/big"
+	"math/rand"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	gethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/smartcontractkit/chainlink/core/internal/gethwrappers/generated/offchain_aggregator_wrapper"
 	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/services/eth"
 	"github.com/smartcontractkit/chainlink/core/services/log"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
 	"github.com/smartcontractkit/libocr/gethwrappers/offchainaggregator"
-	"github.com/smartcontractkit/libocr/offchainreporting/confighelper"
 	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+	"go.uber.org/zap/zapcore"
 )
 
 var (
@@ -24,108 +37,1776 @@ var (
 
 type (
 	OCRContractConfigTracker struct {
-		ethClient        eth.Client
-		contract         *offchain_aggregator_wrapper.OffchainAggregator
-		contractFilterer *offchainaggregator.OffchainAggregatorFilterer
-		contractCaller   *offchainaggregator.OffchainAggregatorCaller
-		logBroadcaster   log.Broadcaster
-		jobID            int32
-		logger           logger.Logger
+		ethClient      eth.Client
+		contract       *offchain_aggregator_wrapper.OffchainAggregator
+		contractCaller *offchainaggregator.OffchainAggregatorCaller
+		logBroadcaster log.Broadcaster
+		jobID          int32
+		logger         logger.Logger
+		tracer         Tracer
+		clock          utils.AfterNower
+		breaker        *circuitBreaker
+		detailsCache   *configDetailsCache
+		heightCache    *blockHeightCache
+		readOnly       bool
+		catchUpDepth   uint64
+		connected      uint32
+
+		addressMismatchLevel    zapcore.Level
+		addressMismatchInterval time.Duration
+		addressMismatchLogger   *rateLimitedLogger
+
+		expectedTypeAndVersions []string
+
+		latestConfig    *ocrtypes.ContractConfig
+		latestConfigLog *types.Log
+		latestConfigMu  sync.RWMutex
+
+		history   []ConfigWithBlock
+		historyMu sync.RWMutex
+
+		pauseMu          sync.Mutex
+		paused           bool
+		pausedBroadcasts []pausedBroadcast
+
+		configListeners   map[chan ocrtypes.ContractConfig]struct{}
+		configListenersMu sync.Mutex
+		reorgListeners    map[chan ReorgEvent]struct{}
+		reorgListenersMu  sync.Mutex
+		eventListeners    map[chan TrackerEvent]struct{}
+		eventListenersMu  sync.Mutex
+
+		verifyConfigDigest bool
+		canonicalLogs      bool
+
+		broadcastObserver func(log.Broadcast)
+		logFilter         func(types.Log) bool
+
+		eagerInitialFetch bool
+
+		// lastConfigApplied is the unix time in nanoseconds at which a config
+		// was last applied, or 0 if none has been
+		lastConfigApplied int64
+
+		roundRequestHistorySize int
+		roundRequestHistory     []RoundRequestWithBlock
+		roundRequestHistoryMu   sync.RWMutex
+		latestRoundRequestAt    time.Time
+		stuckRoundTimeout       time.Duration
+
+		// metrics is built from metricsSink and promRegistry by
+		// newMetricsSink once all options are applied
+		metrics      MetricsSink
+		metricsSink  MetricsSink
+		promRegistry *prometheus.Registry
+
+		deliveryTimeout     time.Duration
+		minDeliveryInterval time.Duration
+		syncDelivery        bool
+
+		failoverClients []eth.Client
+		endpoints       []endpoint
+		activeEndpoint  uint32
+
+		configPolicy ConfigPolicy
+
+		configHistoryStore ConfigHistoryStore
+
+		blockGapThreshold uint64
+		replayOnBlockGap  bool
+
+		subscriptions     map[*OCRContractConfigSubscription]struct{}
+		subscriptionsMu   sync.Mutex
+		recentLogBlocks   []uint64
+		recentLogBlocksMu sync.Mutex
+
+		trustBroadcasterDelivery bool
+
+		configDriftInterval    time.Duration
+		configDriftGracePeriod time.Duration
+
+		codeCheckInterval time.Duration
+		noCode            uint32
+
+		configAgeInterval time.Duration
+
+		configSetParser ConfigSetParser
+
+		blockTimestamps *blockTimestampCache
+
+		latestTransmission   *Transmission
+		latestTransmissionMu sync.RWMutex
+
+		maxLogDataSize int
+
+		maxStartupDelay  time.Duration
+		startupDelayOnce sync.Once
+
+		pausedHighWaterMark int
+
+		rpcSemaphore chan struct{}
+
+		strictParsingThreshold   uint32
+		consecutiveParseFailures uint32
+		lastParseErr             error
+		parseFailuresMu          sync.Mutex
+
+		// decimals and description are immutable, so are cached once read
+		decimals    *uint8
+		description *string
+		metadataMu  sync.Mutex
+	}
+
+	// TopicFilteringBroadcaster is implemented by log broadcasters that only
+	// deliver the logs of a contract with certain topics
+	TopicFilteringBroadcaster interface {
+		log.Broadcaster
+		SubscribedTopics(address gethCommon.Address) []gethCommon.Hash
+	}
+
+	pausedBroadcast struct {
+		sub *OCRContractConfigSubscription
+		lb  log.Broadcast
+	}
+
+	// ConfigWithBlock is a contract config along with the block it was set in
+	ConfigWithBlock struct {
+		ocrtypes.ContractConfig
+		BlockNumber uint64
+	}
+
+	// RoundRequestWithBlock is a RoundRequested event along with the block it
+	// was emitted in
+	RoundRequestWithBlock struct {
+		offchainaggregator.OffchainAggregatorRoundRequested
+		BlockNumber uint64
+	}
+
+	// ReorgEvent describes a ConfigSet or RoundRequested log removed by a
+	// reorg
+	ReorgEvent struct {
+		// Event is the name of the removed event
+		Event        string
+		ConfigDigest ocrtypes.ConfigDigest
+		BlockNumber  uint64
+		BlockHash    gethCommon.Hash
 	}
+
+	// Transmission is the monitoring view of a NewTransmission event
+	Transmission struct {
+		AggregatorRoundID uint32
+		Answer            *big.Int
+		Transmitter       gethCommon.Address
+		ConfigDigest      ocrtypes.ConfigDigest
+		Epoch             uint32
+		Round             uint8
+		ObservationCount  int
+		BlockNumber       uint64
+		TxIndex           uint
+		LogIndex          uint
+	}
+
+	// configDetailsCache holds the result of the last LatestConfigDetails call
+	// until it is invalidated by a new head or a ConfigSet log
+	configDetailsCache struct {
+		mu             sync.Mutex
+		valid          bool
+		changedInBlock uint64
+		configDigest   ocrtypes.ConfigDigest
+		// filling is closed when the in-flight fetch on a miss completes, and
+		// is nil when there is none
+		filling chan struct{}
+		// generation is incremented on every invalidation, so that a fetch
+		// that raced with one is not cached
+		generation uint64
+	}
+
+	// blockHeightCache holds the result of the last LatestBlockHeight call for
+	// ttl after it was fetched
+	blockHeightCache struct {
+		mu        sync.Mutex
+		ttl       time.Duration
+		height    uint64
+		fetchedAt time.Time
+		// filling is closed when the fetch in flight completes, and is nil if
+		// none is
+		filling chan struct{}
+	}
+
+	// OCRContractConfigTrackerOption configures optional behaviour of the
+	// OCRContractConfigTracker
+	OCRContractConfigTrackerOption func(*OCRContractConfigTracker)
 )
 
+// WithClock overrides the clock used for all time-based logic in the tracker
+func WithClock(clock utils.AfterNower) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.clock = clock
+	}
+}
+
+// WithCircuitBreaker makes the tracker fail RPC calls fast for the given
+// cooldown once failureThreshold consecutive calls have failed
+func WithCircuitBreaker(failureThreshold uint32, cooldown time.Duration) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.breaker.failureThreshold = failureThreshold
+		oc.breaker.cooldown = cooldown
+	}
+}
+
+// WithConfigDetailsCache serves LatestConfigDetails from a cache that is
+// invalidated whenever a ConfigSet log is handled or OnNewLongestChain is
+// called with a head at or after the cached block. The tracker must be fed
+// heads for the cache to pick up configs from missed logs.
+func WithConfigDetailsCache() OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.detailsCache = &configDetailsCache{}
+	}
+}
+
+// WithBlockHeightCache serves LatestBlockHeight from the result of the last
+// call made to the node within ttl, so that frequent calls from libocr do not
+// each hit the RPC
+func WithBlockHeightCache(ttl time.Duration) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.heightCache = &blockHeightCache{ttl: ttl}
+	}
+}
+
+// WithReadOnly puts the tracker in audit mode: configs are recorded in
+// ConfigHistory instead of being delivered, and subscriptions have no
+// Configs channel. Used to observe a contract without running OCR.
+func WithReadOnly() OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.readOnly = true
+	}
+}
+
+// WithConfigDigestVerification makes subscriptions recompute the digest of
+// each ConfigSet log, and check the latest config against the digest reported
+// by the contract, before applying it, so that a node serving tampered logs
+// is detected. This costs an extra RPC per log.
+func WithConfigDigestVerification() OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.verifyConfigDigest = true
+	}
+}
+
+// WithCanonicalLogs makes ConfigFromLogs and range scans check that every
+// fetched ConfigSet log is still in the canonical chain, re-fetching logs for
+// any block that was reorged out mid-scan. This costs a header RPC per block
+// containing a log.
+func WithCanonicalLogs() OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.canonicalLogs = true
+	}
+}
+
+// WithBroadcastObserver registers a debug hook that sees every broadcast
+// delivered to the tracker's subscriptions, before the consumed check. The
+// observer must be non-blocking and must not call methods on the broadcast
+// that change its state; panics in the observer are recovered and logged.
+func WithBroadcastObserver(observer func(log.Broadcast)) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.broadcastObserver = observer
+	}
+}
+
+// WithLogFilter makes subscriptions skip logs for which filter returns false,
+// for instance logs from a block range known to be bad. Skipped logs are
+// marked consumed so that they are not redelivered. The filter must be
+// non-blocking. By default every log is handled.
+func WithLogFilter(filter func(types.Log) bool) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.logFilter = filter
+	}
+}
+
+// WithEagerInitialFetch makes SubscribeToNewConfigs fetch the contract's
+// current config and deliver it on the new subscription straight away, so
+// that libocr can start without first polling LatestConfigDetails
+func WithEagerInitialFetch() OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.eagerInitialFetch = true
+	}
+}
+
+// WithConfigAgeUpdates makes subscriptions update the
+// seconds-since-config-applied gauge every interval. Without it the gauge is
+// only set to 0 when a config is applied.
+func WithConfigAgeUpdates(interval time.Duration) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.configAgeInterval = interval
+	}
+}
+
+// WithRoundRequestHistory makes subscriptions keep the last size
+// RoundRequested events, for debugging stuck rounds via RoundRequestHistory.
+// The latest round request is always kept, so a size below 1 has no effect.
+func WithRoundRequestHistory(size int) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		if size > 1 {
+			oc.roundRequestHistorySize = size
+		}
+	}
+}
+
+// WithStuckRoundTimeout sets how long after a round request without a
+// transmission ExpectedNextEpoch reports the round as stuck. Defaults to
+// DefaultStuckRoundTimeout.
+func WithStuckRoundTimeout(timeout time.Duration) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.stuckRoundTimeout = timeout
+	}
+}
+
+// WithMetricsSink sends the tracker's metrics to the given sink instead of
+// Prometheus, e.g. NewStatsdMetricsSink for a statsd pipeline. Combined with
+// WithPrometheusRegistry, metrics are sent to both.
+func WithMetricsSink(sink MetricsSink) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.metricsSink = sink
+	}
+}
+
+// WithPrometheusRegistry registers the tracker's Prometheus metrics with the
+// given registry instead of the default one, isolating them from other
+// trackers in the process. Trackers given the same registry share metrics.
+// Combined with WithMetricsSink, metrics are sent to both.
+func WithPrometheusRegistry(registry *prometheus.Registry) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.promRegistry = registry
+	}
+}
+
+// WithDeliveryTimeout sets how long a subscription waits for libocr to receive
+// a config before retrying, so that a newer config can be delivered instead
+// if one has arrived. Defaults to OCRContractConfigSubscriptionHandleLogTimeout.
+func WithDeliveryTimeout(timeout time.Duration) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.deliveryTimeout = timeout
+	}
+}
+
+// WithSynchronousDelivery makes subscriptions deliver configs from HandleLog
+// itself instead of through a background worker. The Configs channel holds a
+// single config and a newer config replaces one not yet received, so
+// HandleLog never waits on libocr, but persisting the config to the history
+// store happens on the log broadcaster's goroutine and the delivery timeout
+// and minimum delivery interval do not apply. Suited to low-volume
+// deployments and tests.
+func WithSynchronousDelivery() OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.syncDelivery = true
+	}
+}
+
+// WithMinDeliveryInterval throttles config deliveries to libocr, which
+// restarts the protocol on every new config. A config arriving within the
+// interval of the last delivery is held until the interval has elapsed, and
+// only the latest config held is delivered. Disabled by default.
+func WithMinDeliveryInterval(interval time.Duration) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.minDeliveryInterval = interval
+	}
+}
+
+// WithBlockGapDetection makes subscriptions warn when more than threshold
+// blocks are skipped between consecutive handled logs, which suggests that
+// logs were dropped. If replay is set, the skipped blocks are then re-scanned
+// for a missed config with ReplayFromBlock.
+func WithBlockGapDetection(threshold uint64, replay bool) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.blockGapThreshold = threshold
+		oc.replayOnBlockGap = replay
+	}
+}
+
+// WithTrustBroadcasterDelivery skips the WasAlreadyConsumed and MarkConsumed
+// database calls for every log, relying on the log broadcaster delivering
+// each log at most once per process lifetime. Consumption is then not
+// persisted, so logs still within the broadcaster's backfill window are
+// handled again after a restart. The tracker handles repeated logs
+// idempotently, so this trades durability of consumption for throughput.
+func WithTrustBroadcasterDelivery() OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.trustBroadcasterDelivery = true
+	}
+}
+
+// WithMaxLogDataSize sets the size in bytes above which a log's data is
+// skipped without being parsed, guarding against contracts that emit huge
+// logs to exhaust memory. A size of 0 disables the limit. Defaults to
+// OCRContractMaxLogDataSize.
+func WithMaxLogDataSize(size int) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.maxLogDataSize = size
+	}
+}
+
+// WithStartupDelay makes the first subscription wait a random duration of up
+// to maxDelay before its first RPC call, the catch-up scan or eager initial
+// fetch, so that trackers started together do not all hit the RPC node at
+// once. Disabled by default.
+func WithStartupDelay(maxDelay time.Duration) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.maxStartupDelay = maxDelay
+	}
+}
+
+// WithPausedHighWaterMark sets the number of logs buffered while paused at
+// which a warning is logged and MetricPausedHighWater incremented, ahead of
+// logs being dropped at MaxPausedBroadcasts. Defaults to half of
+// MaxPausedBroadcasts.
+func WithPausedHighWaterMark(mark int) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.pausedHighWaterMark = mark
+	}
+}
+
+// WithRPCSemaphore caps the number of concurrent RPC calls at the capacity of
+// sem. Every call sends on sem before it is made, waiting for as long as the
+// call's context allows, and receives from it when done. Sharing sem between
+// trackers bounds their combined use of the node's connection pool. Calls are
+// unbounded by default.
+func WithRPCSemaphore(sem chan struct{}) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.rpcSemaphore = sem
+	}
+}
+
+// WithStrictParsing makes Healthy report an error once threshold consecutive
+// logs of a handled event have failed to parse, since that indicates a
+// mismatch between the contract and its ABI rather than a one-off bad log.
+// A log that parses resets the count.
+func WithStrictParsing(threshold uint32) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.strictParsingThreshold = threshold
+	}
+}
+
+// WithExpectedTypeAndVersions makes NewOCRContractConfigTrackerChecked fail
+// unless the contract's typeAndVersion() is one of the given values
+func WithExpectedTypeAndVersions(typeAndVersions ...string) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.expectedTypeAndVersions = typeAndVersions
+	}
+}
+
+// WithCatchUpOnSubscribe makes SubscribeToNewConfigs scan for ConfigSet logs
+// emitted while the node was not subscribed, starting from the later of the
+// last config seen and safeDepth blocks behind the head, and deliver the
+// latest one found
+func WithCatchUpOnSubscribe(safeDepth uint64) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.catchUpDepth = safeDepth
+	}
+}
+
+// WithAddressMismatchLogging sets the level of the message logged when the
+// broadcaster delivers a log from another contract, and logs it at most once
+// per interval. These are expected transiently during reconfiguration.
+func WithAddressMismatchLogging(level zapcore.Level, interval time.Duration) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.addressMismatchLevel = level
+		oc.addressMismatchInterval = interval
+	}
+}
+
+// WithTracer wraps the tracker's RPC calls and config deliveries in spans
+func WithTracer(tracer Tracer) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.tracer = tracer
+	}
+}
+
+// NewOCRContractConfigTracker returns a tracker for the OffchainAggregator at
+// the contract's address.
+//
+// contractCaller may be nil if only logs are to be processed, for example
+// with WithReadOnly. LatestConfigDetails then returns ErrNoContractCaller, as
+// do the features that rely on it: WithConfigDigestVerification,
+// WithConfigDriftCheck, the fallback of WithCatchUpOnSubscribe, and
+// NewOCRContractConfigTrackerChecked.
 func NewOCRContractConfigTracker(
 	contract *offchain_aggregator_wrapper.OffchainAggregator,
-	contractFilterer *offchainaggregator.OffchainAggregatorFilterer,
 	contractCaller *offchainaggregator.OffchainAggregatorCaller,
 	ethClient eth.Client,
 	logBroadcaster log.Broadcaster,
 	jobID int32,
 	logger logger.Logger,
+	opts ...OCRContractConfigTrackerOption,
 ) (o *OCRContractConfigTracker, err error) {
-	return &OCRContractConfigTracker{
-		ethClient,
-		contract,
-		contractFilterer,
-		contractCaller,
-		logBroadcaster,
-		jobID,
-		logger,
-	}, nil
-}
-
-func (oc *OCRContractConfigTracker) SubscribeToNewConfigs(context.Context) (ocrtypes.ContractConfigSubscription, error) {
+	// Fields not set here default to their zero value and are set by options
+	o = &OCRContractConfigTracker{
+		ethClient:               ethClient,
+		contract:                contract,
+		contractCaller:          contractCaller,
+		logBroadcaster:          logBroadcaster,
+		jobID:                   jobID,
+		logger:                  logger,
+		clock:                   utils.Clock{},
+		breaker:                 &circuitBreaker{},
+		addressMismatchLevel:    zapcore.ErrorLevel,
+		deliveryTimeout:         OCRContractConfigSubscriptionHandleLogTimeout,
+		configPolicy:            NoopConfigPolicy{},
+		maxLogDataSize:          OCRContractMaxLogDataSize,
+		pausedHighWaterMark:     MaxPausedBroadcasts / 2,
+		stuckRoundTimeout:       DefaultStuckRoundTimeout,
+		roundRequestHistorySize: 1,
+		blockTimestamps:         newBlockTimestampCache(DefaultBlockTimestampCacheSize),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if err = o.initEndpoints(); err != nil {
+		return nil, err
+	}
+	o.breaker.clock = o.clock
+	o.metrics = o.newMetricsSink()
+	o.addressMismatchLogger = newRateLimitedLogger(o.logger, o.addressMismatchLevel, o.addressMismatchInterval, o.clock)
+	return o, nil
+}
+
+// NewOCRContractConfigTrackerChecked is like NewOCRContractConfigTracker but
+// additionally verifies that an OffchainAggregator is deployed and responding
+// at the contract address
+func NewOCRContractConfigTrackerChecked(
+	ctx context.Context,
+	contract *offchain_aggregator_wrapper.OffchainAggregator,
+	contractCaller *offchainaggregator.OffchainAggregatorCaller,
+	ethClient eth.Client,
+	logBroadcaster log.Broadcaster,
+	jobID int32,
+	logger logger.Logger,
+	opts ...OCRContractConfigTrackerOption,
+) (*OCRContractConfigTracker, error) {
+	oc, err := NewOCRContractConfigTracker(contract, contractCaller, ethClient, logBroadcaster, jobID, logger, opts...)
+	if err != nil {
+		return nil, err
+	}
+	code, err := ethClient.CodeAt(ctx, contract.Address(), nil)
+	if err != nil {
+		return nil, oc.wrapErr(err, "could not fetch contract code")
+	}
+	if len(code) == 0 {
+		return nil, oc.errorf("no OffchainAggregator at address: address has no code")
+	}
+	if _, _, err := oc.LatestConfigDetails(ctx); err != nil {
+		return nil, oc.wrapErr(err, "no OffchainAggregator at address")
+	}
+	if err := oc.checkTypeAndVersion(ctx); err != nil {
+		return nil, err
+	}
+	return oc, nil
+}
+
+// call makes an RPC call through the circuit breaker, once a slot of the RPC
+// semaphore, if any, has been acquired. It fails fast with
+// ErrContractHasNoCode while the contract is known to have no code.
+func (oc *OCRContractConfigTracker) call(ctx context.Context, fn func() error) error {
+	if oc.hasNoCode() {
+		return ErrContractHasNoCode
+	}
+	return oc.callUnchecked(ctx, fn)
+}
+
+// callUnchecked is call without the check for contract code
+func (oc *OCRContractConfigTracker) callUnchecked(ctx context.Context, fn func() error) error {
+	if oc.rpcSemaphore != nil {
+		select {
+		case oc.rpcSemaphore <- struct{}{}:
+			defer func() { <-oc.rpcSemaphore }()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return oc.breaker.call(fn)
+}
+
+// checkSubscribedTopics returns an error if the log broadcaster filters logs
+// by topic and would not deliver every topic in OCRTrackedTopics
+func (oc *OCRContractConfigTracker) checkSubscribedTopics() error {
+	filtering, ok := oc.logBroadcaster.(TopicFilteringBroadcaster)
+	if !ok {
+		return nil
+	}
+	subscribed := make(map[gethCommon.Hash]struct{})
+	for _, topic := range filtering.SubscribedTopics(oc.contract.Address()) {
+		subscribed[topic] = struct{}{}
+	}
+	for _, topic := range OCRTrackedTopics() {
+		if _, exists := subscribed[topic]; !exists {
+			return oc.errorf("log broadcaster is not subscribed to tracked topic 0x%x", topic)
+		}
+	}
+	return nil
+}
+
+// errorf returns an error annotated with the tracker's job ID and contract
+// address, so that errors from trackers of different jobs can be told apart
+func (oc *OCRContractConfigTracker) errorf(format string, args ...interface{}) error {
+	return errors.Errorf("%s%s", fmt.Sprintf(format, args...), oc.errSuffix())
+}
+
+// wrapErr wraps err with msg, annotated like errorf. It returns nil if err
+// is nil.
+func (oc *OCRContractConfigTracker) wrapErr(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	if strings.Contains(err.Error(), oc.errSuffix()) {
+		// Already annotated further down the call stack
+		return errors.Wrap(err, msg)
+	}
+	return errors.Wrapf(err, "%s%s", msg, oc.errSuffix())
+}
+
+func (oc *OCRContractConfigTracker) errSuffix() string {
+	return fmt.Sprintf(" (jobID %d, contract %s)", oc.jobID, oc.contract.Address().Hex())
+}
+
+func (oc *OCRContractConfigTracker) SubscribeToNewConfigs(ctx context.Context) (ocrtypes.ContractConfigSubscription, error) {
+	if err := oc.checkSubscribedTopics(); err != nil {
+		return nil, err
+	}
+	var ch chan ocrtypes.ContractConfig
+	if !oc.readOnly && oc.syncDelivery {
+		ch = make(chan ocrtypes.ContractConfig, 1)
+	} else if !oc.readOnly {
+		ch = make(chan ocrtypes.ContractConfig)
+	}
+	// Fields not set here default to their zero value
 	sub := &OCRContractConfigSubscription{
-		oc.logger,
-		oc.contract,
-		make(chan ocrtypes.ContractConfig),
-		make(chan ocrtypes.ContractConfig),
-		nil,
-		nil,
-		sync.Mutex{},
-		oc,
-		sync.Once{},
-		make(chan struct{}),
+		logger:     oc.logger,
+		contract:   oc.contract,
+		ch:         ch,
+		chIncoming: make(chan ocrtypes.ContractConfig),
+		oc:         oc,
+		chStop:     make(chan struct{}),
+		chNewer:    make(chan struct{}, 1),
+	}
+	// Start the worker before registering since the broadcaster may call
+	// OnConnect/HandleLog as soon as the listener is added
+	if !oc.syncDelivery {
+		sub.start()
 	}
 	connected := oc.logBroadcaster.Register(oc.contract, sub)
+	oc.setConnected(connected)
 	if !connected {
-		return nil, errors.New("Failed to register with logBroadcaster")
+		sub.stopWorker()
+		return nil, oc.errorf("failed to register with logBroadcaster")
+	}
+	oc.addSubscription(sub)
+	oc.notifyEventListeners(TrackerEvent{Type: TrackerEventStarted})
+	// The initial fetch may wait out the startup delay, so is not run on
+	// libocr's startup path
+	sub.goTracked(sub.initialFetch)
+	if oc.configDriftInterval > 0 {
+		sub.goTracked(sub.runConfigDriftCheck)
+	}
+	if oc.codeCheckInterval > 0 {
+		sub.goTracked(sub.runCodeCheck)
+	}
+	if oc.configAgeInterval > 0 {
+		sub.goTracked(sub.runConfigAgeUpdates)
 	}
-	sub.start()
 
 	return sub, nil
 }
 
+// waitStartupDelay waits out the startup delay on the first call, returning
+// the context's error if it is done first
+func (oc *OCRContractConfigTracker) waitStartupDelay(ctx context.Context) (err error) {
+	oc.startupDelayOnce.Do(func() {
+		if oc.maxStartupDelay <= 0 {
+			return
+		}
+		delay := time.Duration(rand.Int63n(int64(oc.maxStartupDelay)))
+		oc.logger.Debugw("OCRContract: delaying startup", "delay", delay)
+		select {
+		case <-oc.clock.After(delay):
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	})
+	return err
+}
+
 func (oc *OCRContractConfigTracker) LatestConfigDetails(ctx context.Context) (changedInBlock uint64, configDigest ocrtypes.ConfigDigest, err error) {
-	opts := bind.CallOpts{Context: ctx, Pending: false}
-	result, err := oc.contractCaller.LatestConfigDetails(&opts)
+	if oc.contractCaller == nil {
+		return 0, configDigest, ErrNoContractCaller
+	}
+	if oc.detailsCache != nil {
+		return oc.cachedLatestConfigDetails(ctx)
+	}
+	return oc.latestConfigDetails(ctx)
+}
+
+func (oc *OCRContractConfigTracker) latestConfigDetails(ctx context.Context) (changedInBlock uint64, configDigest ocrtypes.ConfigDigest, err error) {
+	ctx, span := oc.startSpan(ctx, "LatestConfigDetails")
+	defer func() { endSpan(span, err) }()
+
+	return oc.callLatestConfigDetails(bind.CallOpts{Context: ctx, Pending: false})
+}
+
+// cachedLatestConfigDetails serves LatestConfigDetails from the cache. On a
+// miss one caller fetches the details while concurrent callers wait for it,
+// and the cache lock is never held across the call.
+func (oc *OCRContractConfigTracker) cachedLatestConfigDetails(ctx context.Context) (changedInBlock uint64, configDigest ocrtypes.ConfigDigest, err error) {
+	cache := oc.detailsCache
+	cache.mu.Lock()
+	for !cache.valid && cache.filling != nil {
+		filling := cache.filling
+		cache.mu.Unlock()
+		select {
+		case <-filling:
+		case <-ctx.Done():
+			return 0, configDigest, oc.wrapErr(ctx.Err(), "gave up waiting for LatestConfigDetails")
+		}
+		// If the fetch failed, this caller makes the next attempt
+		cache.mu.Lock()
+	}
+	if cache.valid {
+		defer cache.mu.Unlock()
+		return cache.changedInBlock, cache.configDigest, nil
+	}
+	filling := make(chan struct{})
+	cache.filling = filling
+	generation := cache.generation
+	cache.mu.Unlock()
+
+	changedInBlock, configDigest, err = oc.latestConfigDetails(ctx)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.filling = nil
+	close(filling)
+	if err == nil && cache.generation == generation {
+		cache.valid = true
+		cache.changedInBlock = changedInBlock
+		cache.configDigest = configDigest
+	}
+	return changedInBlock, configDigest, err
+}
+
+// ConfigDetailsAt returns the config details as of the given block, for
+// reconstructing which config was active at that block. The state of old
+// blocks is pruned by full nodes, so this requires an archive node unless the
+// block is recent.
+func (oc *OCRContractConfigTracker) ConfigDetailsAt(ctx context.Context, blockNumber uint64) (changedInBlock uint64, configDigest ocrtypes.ConfigDigest, err error) {
+	if oc.contractCaller == nil {
+		return 0, configDigest, ErrNoContractCaller
+	}
+	ctx, span := oc.startSpan(ctx, "ConfigDetailsAt")
+	defer func() { endSpan(span, err) }()
+
+	return oc.callLatestConfigDetails(bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(blockNumber)})
+}
+
+func (oc *OCRContractConfigTracker) callLatestConfigDetails(opts bind.CallOpts) (changedInBlock uint64, configDigest ocrtypes.ConfigDigest, err error) {
+	var blockNumber uint32
+	var rawDigest [16]byte
+	err = oc.call(opts.Context, func() error {
+		return oc.withFailover(opts.Context, func(e endpoint) error {
+			result, err2 := e.caller.LatestConfigDetails(&opts)
+			if err2 != nil {
+				return err2
+			}
+			blockNumber, rawDigest = result.BlockNumber, result.ConfigDigest
+			return nil
+		})
+	})
 	if err != nil {
-		return 0, configDigest, errors.Wrap(err, "error getting LatestConfigDetails")
+		return 0, configDigest, oc.wrapErr(err, "error getting LatestConfigDetails")
 	}
-	configDigest, err = ocrtypes.BytesToConfigDigest(result.ConfigDigest[:])
+	configDigest, err = ocrtypes.BytesToConfigDigest(rawDigest[:])
 	if err != nil {
-		return 0, configDigest, errors.Wrap(err, "error getting config digest")
+		return 0, configDigest, oc.wrapErr(err, "error getting config digest")
 	}
-	return uint64(result.BlockNumber), configDigest, err
+	return uint64(blockNumber), configDigest, err
 }
 
+// ConfigFromLogs returns the config set in changedInBlock. It becomes the
+// latest config seen by the tracker unless a later one has been seen, so
+// looking up an old block does not roll the tracker back. A config rejected
+// by the config policy is neither returned nor recorded; ErrConfigRejected
+// is returned instead.
 func (oc *OCRContractConfigTracker) ConfigFromLogs(ctx context.Context, changedInBlock uint64) (c ocrtypes.ContractConfig, err error) {
+	ctx, span := oc.startSpan(ctx, "ConfigFromLogs")
+	defer func() { endSpan(span, err) }()
+
+	c, raw, err := oc.configFromLogs(ctx, changedInBlock)
+	if err != nil {
+		return c, err
+	}
+	if !oc.allowedByPolicy(c, changedInBlock) {
+		return ocrtypes.ContractConfig{}, oc.wrapErr(ErrConfigRejected, fmt.Sprintf("ConfigFromLogs got config from block %d", changedInBlock))
+	}
+	oc.setLatestConfig(c, raw)
+	return c, nil
+}
+
+// configFromLogs returns the config set in changedInBlock along with its log
+func (oc *OCRContractConfigTracker) configFromLogs(ctx context.Context, changedInBlock uint64) (c ocrtypes.ContractConfig, latest types.Log, err error) {
+	logs, err := oc.filterConfigSetLogs(ctx, changedInBlock, changedInBlock)
+	if err != nil {
+		return c, latest, oc.wrapErr(err, "ConfigFromLogs could not fetch logs")
+	}
+	if len(logs) == 0 {
+		return c, latest, oc.errorf("ConfigFromLogs found no logs in block %d", changedInBlock)
+	}
+
+	latest = logs[len(logs)-1]
+	c, err = oc.parseConfigSet(latest)
+	if err != nil {
+		return c, latest, oc.wrapErr(err, "ConfigFromLogs got malformed log")
+	}
+	if latest.Address != oc.contract.Address() {
+		return c, latest, oc.errorf("log address of 0x%x does not match the contract address", latest.Address)
+	}
+	return c, latest, nil
+}
+
+// ErrNoContractCaller is returned by methods that call the contract if the
+// tracker was constructed without a contract caller
+var ErrNoContractCaller = errors.New("contract caller not configured")
+
+// ErrNoConfigLogs is returned by EarliestConfig if no ConfigSet logs exist
+// in the scanned range
+var ErrNoConfigLogs = errors.New("no ConfigSet logs found")
+
+// ErrNoConfigApplied is returned by ConfigAgeBlocks if the tracker has not
+// seen a config yet
+var ErrNoConfigApplied = errors.New("no config has been applied")
+
+// configScanChunkSize is the number of blocks requested per log query when
+// scanning an unbounded range, to stay within the range limits of RPC
+// providers
+const configScanChunkSize = 5000
+
+// EarliestConfig returns the first config set at or after fromBlock, along
+// with the block it was set in. The range up to the head is scanned forward
+// in chunks of configScanChunkSize blocks, stopping at the first chunk that
+// contains a config.
+func (oc *OCRContractConfigTracker) EarliestConfig(ctx context.Context, fromBlock uint64) (c ocrtypes.ContractConfig, blockNumber uint64, err error) {
+	ctx, span := oc.startSpan(ctx, "EarliestConfig")
+	defer func() { endSpan(span, err) }()
+
+	head, err := oc.LatestBlockHeight(ctx)
+	if err != nil {
+		return c, 0, oc.wrapErr(err, "EarliestConfig could not fetch head")
+	}
+	for start := fromBlock; start <= head; start += configScanChunkSize {
+		end := start + configScanChunkSize - 1
+		if end > head {
+			end = head
+		}
+		configs, err := oc.configsBetween(ctx, start, end)
+		if err != nil {
+			return c, 0, oc.wrapErr(err, fmt.Sprintf("EarliestConfig could not scan blocks %d to %d", start, end))
+		}
+		if len(configs) > 0 {
+			return configs[0].ContractConfig, configs[0].BlockNumber, nil
+		}
+	}
+	return c, 0, ErrNoConfigLogs
+}
+
+func (oc *OCRContractConfigTracker) filterConfigSetLogs(ctx context.Context, fromBlock, toBlock uint64) (logs []types.Log, err error) {
+	logs, err = oc.fetchConfigSetLogs(ctx, fromBlock, toBlock)
+	if err != nil || !oc.canonicalLogs {
+		return logs, err
+	}
+	return oc.ensureCanonicalLogs(ctx, logs)
+}
+
+func (oc *OCRContractConfigTracker) fetchConfigSetLogs(ctx context.Context, fromBlock, toBlock uint64) (logs []types.Log, err error) {
+	return oc.fetchLogs(ctx, fromBlock, toBlock, OCRContractConfigSet)
+}
+
+// fetchLogs returns the contract's logs with the given topic in the block
+// range, inclusive, in the order they were emitted
+func (oc *OCRContractConfigTracker) fetchLogs(ctx context.Context, fromBlock, toBlock uint64, topic gethCommon.Hash) (logs []types.Log, err error) {
 	q := ethereum.FilterQuery{
-		FromBlock: big.NewInt(int64(changedInBlock)),
-		ToBlock:   big.NewInt(int64(changedInBlock)),
+		FromBlock: big.NewInt(int64(fromBlock)),
+		ToBlock:   big.NewInt(int64(toBlock)),
 		Addresses: []gethCommon.Address{oc.contract.Address()},
 		Topics: [][]gethCommon.Hash{
-			{OCRContractConfigSet},
+			{topic},
 		},
 	}
+	err = oc.call(ctx, func() error {
+		return oc.withFailover(ctx, func(e endpoint) (err2 error) {
+			logs, err2 = e.client.FilterLogs(ctx, q)
+			return err2
+		})
+	})
+	sortLogs(logs)
+	return logs, err
+}
+
+// logBefore reports whether log a was emitted before log b. Some RPC
+// providers return log indices relative to the transaction rather than the
+// block, so logs are ordered by transaction index before log index; this is
+// correct for either kind of index.
+func logBefore(a, b types.Log) bool {
+	if a.BlockNumber != b.BlockNumber {
+		return a.BlockNumber < b.BlockNumber
+	}
+	if a.TxIndex != b.TxIndex {
+		return a.TxIndex < b.TxIndex
+	}
+	return a.Index < b.Index
+}
+
+// sortLogs sorts the logs in the order they were emitted, see logBefore
+func sortLogs(logs []types.Log) {
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logBefore(logs[i], logs[j])
+	})
+}
+
+// maxCanonicalLogsAttempts bounds the number of times logs from reorged
+// blocks are re-fetched before giving up
+const maxCanonicalLogsAttempts = 3
+
+// ensureCanonicalLogs replaces logs whose block hash no longer matches the
+// canonical header at that height with freshly fetched logs for that block,
+// so that callers never see a mix of logs from different forks
+func (oc *OCRContractConfigTracker) ensureCanonicalLogs(ctx context.Context, logs []types.Log) ([]types.Log, error) {
+	for attempt := 1; ; attempt++ {
+		orphaned, err := oc.orphanedBlocks(ctx, logs)
+		if err != nil {
+			return nil, err
+		}
+		if len(orphaned) == 0 {
+			return logs, nil
+		}
+		if attempt == maxCanonicalLogsAttempts {
+			return nil, oc.errorf("logs in %d block(s) still not canonical after %d attempts", len(orphaned), attempt)
+		}
+		oc.logger.Warnw("OCRContract: refetching config set logs from reorged blocks", "blocks", len(orphaned), "attempt", attempt)
+
+		var canonical []types.Log
+		for _, l := range logs {
+			if _, exists := orphaned[l.BlockNumber]; !exists {
+				canonical = append(canonical, l)
+			}
+		}
+		for blockNumber := range orphaned {
+			refetched, err := oc.fetchConfigSetLogs(ctx, blockNumber, blockNumber)
+			if err != nil {
+				return nil, oc.wrapErr(err, fmt.Sprintf("could not refetch logs for block %d", blockNumber))
+			}
+			canonical = append(canonical, refetched...)
+		}
+		sortLogs(canonical)
+		logs = canonical
+	}
+}
 
-	logs, err := oc.ethClient.FilterLogs(ctx, q)
+// orphanedBlocks returns the numbers of the blocks whose logs have a block
+// hash that differs from the canonical header at that height
+func (oc *OCRContractConfigTracker) orphanedBlocks(ctx context.Context, logs []types.Log) (map[uint64]struct{}, error) {
+	orphaned := make(map[uint64]struct{})
+	canonicalHashes := make(map[uint64]gethCommon.Hash)
+	for _, l := range logs {
+		hash, exists := canonicalHashes[l.BlockNumber]
+		if !exists {
+			h, err := oc.headerByNumber(ctx, l.BlockNumber)
+			if err != nil {
+				return nil, err
+			}
+			hash = h.Hash
+			canonicalHashes[l.BlockNumber] = hash
+		}
+		if hash != l.BlockHash {
+			orphaned[l.BlockNumber] = struct{}{}
+		}
+	}
+	return orphaned, nil
+}
+
+func (oc *OCRContractConfigTracker) headerByNumber(ctx context.Context, blockNumber uint64) (*models.Head, error) {
+	var h *models.Head
+	err := oc.call(ctx, func() error {
+		return oc.withFailover(ctx, func(e endpoint) (err2 error) {
+			h, err2 = e.client.HeaderByNumber(ctx, big.NewInt(int64(blockNumber)))
+			return err2
+		})
+	})
 	if err != nil {
-		return c, err
+		return nil, oc.wrapErr(err, fmt.Sprintf("could not fetch header for block %d", blockNumber))
 	}
-	if len(logs) == 0 {
-		return c, errors.Errorf("ConfigFromLogs: OCRContract with address 0x%x has no logs", oc.contract.Address())
+	if h == nil {
+		return nil, oc.errorf("got nil head for block %d", blockNumber)
 	}
+	return h, nil
+}
 
-	latest, err := oc.contractFilterer.ParseConfigSet(logs[len(logs)-1])
+// configsBetween returns all configs set in the given block range, inclusive,
+// in the order they were emitted
+func (oc *OCRContractConfigTracker) configsBetween(ctx context.Context, fromBlock, toBlock uint64) ([]ConfigWithBlock, error) {
+	logs, err := oc.filterConfigSetLogs(ctx, fromBlock, toBlock)
 	if err != nil {
-		return c, errors.Wrap(err, "ConfigFromLogs failed to ParseConfigSet")
+		return nil, err
 	}
-	latest.Raw = logs[len(logs)-1]
-	if latest.Raw.Address != oc.contract.Address() {
-		return c, errors.Errorf("log address of 0x%x does not match configured contract address of 0x%x", latest.Raw.Address, oc.contract.Address())
+	var configs []ConfigWithBlock
+	for _, raw := range logs {
+		if raw.Address != oc.contract.Address() {
+			return nil, oc.errorf("log address of 0x%x does not match the contract address", raw.Address)
+		}
+		cc, err := oc.parseConfigSet(raw)
+		if err != nil {
+			return nil, oc.wrapErr(err, "got malformed log")
+		}
+		configs = append(configs, ConfigWithBlock{cc, raw.BlockNumber})
 	}
-	return confighelper.ContractConfigFromConfigSetEvent(*latest), err
+	return configs, nil
 }
 
 func (oc *OCRContractConfigTracker) LatestBlockHeight(ctx context.Context) (blockheight uint64, err error) {
-	h, err := oc.ethClient.HeaderByNumber(ctx, nil)
+	ctx, span := oc.startSpan(ctx, "LatestBlockHeight")
+	defer func() { endSpan(span, err) }()
+
+	if oc.heightCache == nil {
+		return oc.BlockHeightFor(ctx, "latest")
+	}
+	cache := oc.heightCache
+	cache.mu.Lock()
+	for !cache.fresh(oc.clock.Now()) && cache.filling != nil {
+		filling := cache.filling
+		cache.mu.Unlock()
+		select {
+		case <-filling:
+		case <-ctx.Done():
+			return 0, oc.wrapErr(ctx.Err(), "gave up waiting for LatestBlockHeight")
+		}
+		// If the fetch failed, this caller makes the next attempt
+		cache.mu.Lock()
+	}
+	now := oc.clock.Now()
+	if cache.fresh(now) {
+		defer cache.mu.Unlock()
+		return cache.height, nil
+	}
+	filling := make(chan struct{})
+	cache.filling = filling
+	cache.mu.Unlock()
+
+	blockheight, err = oc.BlockHeightFor(ctx, "latest")
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.filling = nil
+	close(filling)
 	if err != nil {
 		return 0, err
 	}
+	cache.height = blockheight
+	cache.fetchedAt = now
+	return blockheight, nil
+}
+
+// fresh reports whether the cached height is still within its ttl at now.
+// The caller must hold mu.
+func (c *blockHeightCache) fresh(now time.Time) bool {
+	return !c.fetchedAt.IsZero() && now.Sub(c.fetchedAt) < c.ttl
+}
+
+// configDetails is the result of LatestConfigDetails
+type configDetails struct {
+	changedInBlock uint64
+	configDigest   ocrtypes.ConfigDigest
+}
+
+// refreshHeadAndConfig fetches the latest block height and config details in
+// a single batch request, falling back to separate requests if the node
+// rejects the batch
+func (oc *OCRContractConfigTracker) refreshHeadAndConfig(ctx context.Context) (head uint64, details configDetails, err error) {
+	calldata, err := offchainAggregatorABI.Pack("latestConfigDetails")
+	if err != nil {
+		return 0, details, oc.wrapErr(err, "could not pack latestConfigDetails call")
+	}
+	var h *models.Head
+	var result hexutil.Bytes
+	batch := []rpc.BatchElem{
+		{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{"latest", false},
+			Result: &h,
+		},
+		{
+			Method: "eth_call",
+			Args: []interface{}{
+				map[string]interface{}{"to": oc.contract.Address(), "data": hexutil.Bytes(calldata)},
+				"latest",
+			},
+			Result: &result,
+		},
+	}
+	err = oc.call(ctx, func() error {
+		return oc.withFailover(ctx, func(e endpoint) error {
+			return e.client.BatchCallContext(ctx, batch)
+		})
+	})
+	if err != nil {
+		oc.logger.Debugw("OCRContract: batch request failed, falling back to separate requests", "err", err)
+		head, err = oc.LatestBlockHeight(ctx)
+		if err != nil {
+			return 0, details, err
+		}
+		details.changedInBlock, details.configDigest, err = oc.LatestConfigDetails(ctx)
+		return head, details, err
+	}
+
+	for _, elem := range batch {
+		if elem.Error != nil {
+			return 0, details, oc.wrapErr(elem.Error, fmt.Sprintf("error in batched %s", elem.Method))
+		}
+	}
 	if h == nil {
-		return 0, errors.New("got nil head")
+		return 0, details, oc.errorf("got nil head")
+	}
+	out, err := offchainAggregatorABI.Methods["latestConfigDetails"].Outputs.Unpack(result)
+	if err != nil {
+		return 0, details, oc.wrapErr(err, "could not unpack latestConfigDetails")
+	}
+	if len(out) != 3 {
+		return 0, details, oc.errorf("latestConfigDetails returned %d values, expected 3", len(out))
+	}
+	blockNumber, ok := out[1].(uint32)
+	if !ok {
+		return 0, details, oc.errorf("unexpected type %T for latestConfigDetails blockNumber", out[1])
+	}
+	rawDigest, ok := out[2].([16]byte)
+	if !ok {
+		return 0, details, oc.errorf("unexpected type %T for latestConfigDetails configDigest", out[2])
+	}
+	return uint64(h.Number), configDetails{uint64(blockNumber), ocrtypes.ConfigDigest(rawDigest)}, nil
+}
+
+// blockTags maps block tags to the block number argument of HeaderByNumber
+var blockTags = map[string]*big.Int{
+	"latest":    nil,
+	"pending":   big.NewInt(int64(rpc.PendingBlockNumber)),
+	"safe":      big.NewInt(int64(eth.SafeBlockNumber)),
+	"finalized": big.NewInt(int64(eth.FinalizedBlockNumber)),
+}
+
+// BlockHeightFor returns the height of the block with the given tag, one of
+// "latest", "pending", "safe" or "finalized". Not all chains support the safe
+// and finalized tags.
+func (oc *OCRContractConfigTracker) BlockHeightFor(ctx context.Context, tag string) (blockheight uint64, err error) {
+	number, exists := blockTags[tag]
+	if !exists {
+		return 0, oc.errorf("unknown block tag %q", tag)
+	}
+	var h *models.Head
+	err = oc.call(ctx, func() error {
+		return oc.withFailover(ctx, func(e endpoint) (err2 error) {
+			h, err2 = e.client.HeaderByNumber(ctx, number)
+			return err2
+		})
+	})
+	if err != nil {
+		return 0, oc.wrapErr(err, fmt.Sprintf("could not fetch %s head", tag))
+	}
+	if h == nil {
+		return 0, oc.errorf("got nil %s head", tag)
 	}
 
 	return uint64(h.Number), nil
 }
+
+// setLatestConfig records the config as the latest seen, unless a config
+// from a later log has already been recorded
+func (oc *OCRContractConfigTracker) setLatestConfig(cc ocrtypes.ContractConfig, raw types.Log) {
+	oc.latestConfigMu.Lock()
+	defer oc.latestConfigMu.Unlock()
+	if oc.latestConfigLog != nil && logBefore(raw, *oc.latestConfigLog) {
+		return
+	}
+	oc.latestConfig = &cc
+	oc.latestConfigLog = &raw
+}
+
+// replaceLatestConfig records the config as the latest seen even if it is
+// from an earlier log, for when the latest config was reorged out
+func (oc *OCRContractConfigTracker) replaceLatestConfig(cc ocrtypes.ContractConfig, raw types.Log) {
+	oc.latestConfigMu.Lock()
+	defer oc.latestConfigMu.Unlock()
+	oc.latestConfig = &cc
+	oc.latestConfigLog = &raw
+}
+
+func (oc *OCRContractConfigTracker) getLatestConfigLog() *types.Log {
+	oc.latestConfigMu.RLock()
+	defer oc.latestConfigMu.RUnlock()
+	return oc.latestConfigLog
+}
+
+func (oc *OCRContractConfigTracker) getLatestConfig() *ocrtypes.ContractConfig {
+	oc.latestConfigMu.RLock()
+	defer oc.latestConfigMu.RUnlock()
+	return oc.latestConfig
+}
+
+// LatestConfigDigest returns the digest of the latest config seen by the
+// tracker, if any
+func (oc *OCRContractConfigTracker) LatestConfigDigest() (digest ocrtypes.ConfigDigest, found bool) {
+	cc := oc.getLatestConfig()
+	if cc == nil {
+		return digest, false
+	}
+	return cc.ConfigDigest, true
+}
+
+// CurrentOracles returns the signers and transmitters of the latest config
+// seen by the tracker
+func (oc *OCRContractConfigTracker) CurrentOracles() (signers []gethCommon.Address, transmitters []gethCommon.Address, err error) {
+	cc := oc.getLatestConfig()
+	if cc == nil {
+		return nil, nil, oc.errorf("no config has been seen yet")
+	}
+	if len(cc.Signers) == 0 {
+		return nil, nil, oc.errorf("latest config has no signers")
+	}
+	if len(cc.Signers) != len(cc.Transmitters) {
+		return nil, nil, oc.errorf("latest config has %d signers but %d transmitters", len(cc.Signers), len(cc.Transmitters))
+	}
+	signers = make([]gethCommon.Address, len(cc.Signers))
+	copy(signers, cc.Signers)
+	transmitters = make([]gethCommon.Address, len(cc.Transmitters))
+	copy(transmitters, cc.Transmitters)
+	return signers, transmitters, nil
+}
+
+// configListenerBufferSize is the number of configs buffered for each
+// config listener before the oldest are dropped
+const configListenerBufferSize = 16
+
+// Subscribe returns a channel that receives every config applied by the
+// tracker's subscriptions, independently of the channel consumed by libocr.
+// Every subscriber receives configs in the order they were applied. A
+// subscriber that falls more than configListenerBufferSize configs behind
+// misses the oldest configs it has not received, but never receives them
+// out of order and always receives the latest. The returned func must be
+// called to unsubscribe.
+func (oc *OCRContractConfigTracker) Subscribe() (<-chan ocrtypes.ContractConfig, func()) {
+	return oc.addConfigListener(false)
+}
+
+// SubscribeWithSnapshot is like Subscribe, but first delivers the latest
+// config seen by the tracker, if any, so that late subscribers do not miss
+// the current config. The snapshot may duplicate a config that is being
+// applied concurrently.
+func (oc *OCRContractConfigTracker) SubscribeWithSnapshot() (<-chan ocrtypes.ContractConfig, func()) {
+	return oc.addConfigListener(true)
+}
+
+func (oc *OCRContractConfigTracker) addConfigListener(snapshot bool) (<-chan ocrtypes.ContractConfig, func()) {
+	ch := make(chan ocrtypes.ContractConfig, configListenerBufferSize)
+	oc.configListenersMu.Lock()
+	defer oc.configListenersMu.Unlock()
+	if snapshot {
+		if latest := oc.getLatestConfig(); latest != nil {
+			ch <- *latest
+		}
+	}
+	if oc.configListeners == nil {
+		oc.configListeners = make(map[chan ocrtypes.ContractConfig]struct{})
+	}
+	oc.configListeners[ch] = struct{}{}
+	return ch, func() {
+		oc.configListenersMu.Lock()
+		defer oc.configListenersMu.Unlock()
+		delete(oc.configListeners, ch)
+	}
+}
+
+func (oc *OCRContractConfigTracker) notifyConfigListeners(cc ocrtypes.ContractConfig) {
+	oc.configListenersMu.Lock()
+	defer oc.configListenersMu.Unlock()
+	// Configs are only sent under configListenersMu, so each listener
+	// receives them in the same order
+	for ch := range oc.configListeners {
+		select {
+		case ch <- cc:
+			continue
+		default:
+		}
+		// Make room by dropping the oldest config. The listener may have
+		// received it meanwhile, in which case there is room anyway.
+		select {
+		case dropped := <-ch:
+			oc.logger.Warnw("OCRContract: config listener is full, dropping oldest config", "configDigest", dropped.ConfigDigest)
+		default:
+		}
+		ch <- cc
+	}
+}
+
+// SubscribeReorgs returns a channel that receives an event for every
+// ConfigSet or RoundRequested log the tracker's subscriptions see removed by
+// a reorg. Events are dropped if the subscriber falls more than
+// configListenerBufferSize events behind. The returned function
+// unsubscribes.
+func (oc *OCRContractConfigTracker) SubscribeReorgs() (<-chan ReorgEvent, func()) {
+	ch := make(chan ReorgEvent, configListenerBufferSize)
+	oc.reorgListenersMu.Lock()
+	defer oc.reorgListenersMu.Unlock()
+	if oc.reorgListeners == nil {
+		oc.reorgListeners = make(map[chan ReorgEvent]struct{})
+	}
+	oc.reorgListeners[ch] = struct{}{}
+	return ch, func() {
+		oc.reorgListenersMu.Lock()
+		defer oc.reorgListenersMu.Unlock()
+		delete(oc.reorgListeners, ch)
+	}
+}
+
+func (oc *OCRContractConfigTracker) notifyReorgListeners(event ReorgEvent) {
+	oc.reorgListenersMu.Lock()
+	defer oc.reorgListenersMu.Unlock()
+	for ch := range oc.reorgListeners {
+		select {
+		case ch <- event:
+		default:
+			oc.logger.Warnw("OCRContract: reorg listener is full, dropping event", "event", event.Event, "blockNumber", event.BlockNumber)
+		}
+	}
+}
+
+// WaitForConfigDigest blocks until the tracker applies a config with the
+// given digest, or the context is done
+func (oc *OCRContractConfigTracker) WaitForConfigDigest(ctx context.Context, digest ocrtypes.ConfigDigest) error {
+	configs, unsubscribe := oc.SubscribeWithSnapshot()
+	defer unsubscribe()
+
+	for {
+		select {
+		case cc := <-configs:
+			if cc.ConfigDigest == digest {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (oc *OCRContractConfigTracker) recordHistory(cc ocrtypes.ContractConfig, blockNumber uint64) {
+	oc.historyMu.Lock()
+	defer oc.historyMu.Unlock()
+	oc.history = append(oc.history, ConfigWithBlock{cc, blockNumber})
+}
+
+// ConfigHistory returns every config observed by a read-only tracker, in the
+// order they were handled
+func (oc *OCRContractConfigTracker) ConfigHistory() []ConfigWithBlock {
+	oc.historyMu.RLock()
+	defer oc.historyMu.RUnlock()
+	history := make([]ConfigWithBlock, len(oc.history))
+	copy(history, oc.history)
+	return history
+}
+
+// recordRoundRequest adds the round request to the history. Round requests
+// are identified by block hash and log index, so re-delivery of the same log
+// (e.g. from a re-scan) is a no-op, while a log at the same position in a
+// block that reorged the recorded one replaces it.
+func (oc *OCRContractConfigTracker) recordRoundRequest(rr offchainaggregator.OffchainAggregatorRoundRequested) {
+	oc.roundRequestHistoryMu.Lock()
+	defer oc.roundRequestHistoryMu.Unlock()
+	for i, recorded := range oc.roundRequestHistory {
+		if recorded.Raw.BlockNumber != rr.Raw.BlockNumber || recorded.Raw.Index != rr.Raw.Index {
+			continue
+		}
+		if recorded.Raw.BlockHash == rr.Raw.BlockHash {
+			return
+		}
+		oc.roundRequestHistory = append(oc.roundRequestHistory[:i], oc.roundRequestHistory[i+1:]...)
+		break
+	}
+	// Keep the history in emission order, since a re-scan may record round
+	// requests older than the latest one recorded
+	i := sort.Search(len(oc.roundRequestHistory), func(i int) bool {
+		return logBefore(rr.Raw, oc.roundRequestHistory[i].Raw)
+	})
+	oc.roundRequestHistory = append(oc.roundRequestHistory, RoundRequestWithBlock{})
+	copy(oc.roundRequestHistory[i+1:], oc.roundRequestHistory[i:])
+	oc.roundRequestHistory[i] = RoundRequestWithBlock{rr, rr.Raw.BlockNumber}
+	if i == len(oc.roundRequestHistory)-1 {
+		oc.latestRoundRequestAt = oc.clock.Now()
+	}
+	oc.trimRoundRequestHistory()
+}
+
+func (oc *OCRContractConfigTracker) trimRoundRequestHistory() {
+	if excess := len(oc.roundRequestHistory) - oc.roundRequestHistorySize; excess > 0 {
+		oc.roundRequestHistory = oc.roundRequestHistory[excess:]
+	}
+}
+
+// LatestRoundRequester returns the address that requested the round returned
+// by LatestRoundRequested
+func (oc *OCRContractConfigTracker) LatestRoundRequester() (requester gethCommon.Address, found bool) {
+	rr, found := oc.LatestRoundRequested()
+	if !found {
+		return requester, false
+	}
+	return rr.Requester, true
+}
+
+// RoundRequestReconcileLookback is the number of blocks before the head
+// re-scanned by ReconcileLatestRoundRequested
+const RoundRequestReconcileLookback = 1000
+
+// ReconcileLatestRoundRequested re-scans the RoundRequested logs of the last
+// RoundRequestReconcileLookback blocks and replaces the recorded round
+// requests in that range with them. This recovers round requests missed
+// while disconnected and drops those that were reorged out.
+func (oc *OCRContractConfigTracker) ReconcileLatestRoundRequested(ctx context.Context) (err error) {
+	ctx, span := oc.startSpan(ctx, "ReconcileLatestRoundRequested")
+	defer func() { endSpan(span, err) }()
+
+	head, err := oc.LatestBlockHeight(ctx)
+	if err != nil {
+		return oc.wrapErr(err, "ReconcileLatestRoundRequested could not fetch head")
+	}
+	var fromBlock uint64
+	if head > RoundRequestReconcileLookback {
+		fromBlock = head - RoundRequestReconcileLookback
+	}
+	logs, err := oc.fetchLogs(ctx, fromBlock, head, OCRContractRoundRequested)
+	if err != nil {
+		return oc.wrapErr(err, "ReconcileLatestRoundRequested could not fetch logs")
+	}
+	var scanned []RoundRequestWithBlock
+	for _, raw := range logs {
+		if raw.Address != oc.contract.Address() {
+			return oc.errorf("log address of 0x%x does not match the contract address", raw.Address)
+		}
+		rr, err := ParseOCRRoundRequested(raw)
+		if err != nil {
+			return oc.wrapErr(err, "ReconcileLatestRoundRequested got malformed log")
+		}
+		scanned = append(scanned, RoundRequestWithBlock{*rr, raw.BlockNumber})
+	}
+
+	oc.roundRequestHistoryMu.Lock()
+	defer oc.roundRequestHistoryMu.Unlock()
+	var previous *types.Log
+	if n := len(oc.roundRequestHistory); n > 0 {
+		previous = &oc.roundRequestHistory[n-1].Raw
+	}
+	var history []RoundRequestWithBlock
+	for _, recorded := range oc.roundRequestHistory {
+		if recorded.BlockNumber < fromBlock {
+			history = append(history, recorded)
+		}
+	}
+	oc.roundRequestHistory = append(history, scanned...)
+	oc.trimRoundRequestHistory()
+	if n := len(oc.roundRequestHistory); n > 0 && (previous == nil || logBefore(*previous, oc.roundRequestHistory[n-1].Raw)) {
+		oc.latestRoundRequestAt = oc.clock.Now()
+	}
+	return nil
+}
+
+// DefaultStuckRoundTimeout is the default time after a round request without
+// a transmission at or after its epoch before ExpectedNextEpoch reports the
+// round as stuck, see WithStuckRoundTimeout
+const DefaultStuckRoundTimeout = 5 * time.Minute
+
+// ExpectedNextEpoch returns the epoch libocr should be working on, from the
+// latest round request and transmission for the current config. stuck is
+// true if the round was requested more than the stuck round timeout ago and
+// no transmission at or after its epoch has been seen since.
+func (oc *OCRContractConfigTracker) ExpectedNextEpoch() (epoch uint32, stuck bool) {
+	rr, requested := oc.LatestRoundRequested()
+	transmission, transmitted := oc.LatestTransmission()
+	if !requested {
+		return transmission.Epoch, false
+	}
+	if transmitted && transmission.ConfigDigest == ocrtypes.ConfigDigest(rr.ConfigDigest) && transmission.Epoch >= rr.Epoch {
+		return transmission.Epoch, false
+	}
+	oc.roundRequestHistoryMu.RLock()
+	requestedAt := oc.latestRoundRequestAt
+	oc.roundRequestHistoryMu.RUnlock()
+	return rr.Epoch, oc.clock.Now().Sub(requestedAt) > oc.stuckRoundTimeout
+}
+
+// RoundRequestHistory returns the most recent RoundRequested events, oldest
+// first. Only the latest is kept unless WithRoundRequestHistory was given.
+func (oc *OCRContractConfigTracker) RoundRequestHistory() []RoundRequestWithBlock {
+	oc.roundRequestHistoryMu.RLock()
+	defer oc.roundRequestHistoryMu.RUnlock()
+	history := make([]RoundRequestWithBlock, len(oc.roundRequestHistory))
+	copy(history, oc.roundRequestHistory)
+	return history
+}
+
+// LatestRoundRequested returns the most recent RoundRequested event, unless
+// it was requested for a config that has since been superseded.
+func (oc *OCRContractConfigTracker) LatestRoundRequested() (rr RoundRequestWithBlock, found bool) {
+	oc.roundRequestHistoryMu.RLock()
+	defer oc.roundRequestHistoryMu.RUnlock()
+	if len(oc.roundRequestHistory) == 0 {
+		return rr, false
+	}
+	rr = oc.roundRequestHistory[len(oc.roundRequestHistory)-1]
+	if latest := oc.getLatestConfig(); latest != nil && latest.ConfigDigest != ocrtypes.ConfigDigest(rr.ConfigDigest) {
+		return RoundRequestWithBlock{}, false
+	}
+	return rr, true
+}
+
+// recordTransmission sets the latest transmission, unless a transmission
+// from a later log has already been recorded
+func (oc *OCRContractConfigTracker) recordTransmission(nt offchainaggregator.OffchainAggregatorNewTransmission) {
+	oc.latestTransmissionMu.Lock()
+	defer oc.latestTransmissionMu.Unlock()
+	if latest := oc.latestTransmission; latest != nil {
+		latestRaw := types.Log{BlockNumber: latest.BlockNumber, TxIndex: latest.TxIndex, Index: latest.LogIndex}
+		if !logBefore(latestRaw, nt.Raw) {
+			return
+		}
+	}
+	// The report context is 11 bytes of padding, the 16 byte config digest,
+	// the 4 byte epoch and the 1 byte round
+	ctx := nt.RawReportContext
+	var digest ocrtypes.ConfigDigest
+	copy(digest[:], ctx[11:27])
+	oc.latestTransmission = &Transmission{
+		AggregatorRoundID: nt.AggregatorRoundId,
+		Answer:            new(big.Int).Set(nt.Answer),
+		Transmitter:       nt.Transmitter,
+		ConfigDigest:      digest,
+		Epoch:             binary.BigEndian.Uint32(ctx[27:31]),
+		Round:             ctx[31],
+		ObservationCount:  len(nt.Observations),
+		BlockNumber:       nt.Raw.BlockNumber,
+		TxIndex:           nt.Raw.TxIndex,
+		LogIndex:          nt.Raw.Index,
+	}
+}
+
+// LatestTransmission returns the most recent NewTransmission event seen by
+// the tracker's subscriptions
+func (oc *OCRContractConfigTracker) LatestTransmission() (t Transmission, found bool) {
+	oc.latestTransmissionMu.RLock()
+	defer oc.latestTransmissionMu.RUnlock()
+	if oc.latestTransmission == nil {
+		return t, false
+	}
+	t = *oc.latestTransmission
+	t.Answer = new(big.Int).Set(t.Answer)
+	return t, true
+}
+
+// configStillCanonical reports whether the block containing the most recently
+// seen ConfigSet log is still part of the canonical chain. It returns true if
+// no config log has been seen yet.
+func (oc *OCRContractConfigTracker) configStillCanonical(ctx context.Context) (bool, error) {
+	raw := oc.getLatestConfigLog()
+	if raw == nil {
+		return true, nil
+	}
+	h, err := oc.headerByNumber(ctx, raw.BlockNumber)
+	if err != nil {
+		return false, err
+	}
+	return h.Hash == raw.BlockHash, nil
+}
+
+// ConfigAgeBlocks returns the number of blocks between the block the
+// currently applied config was set in and the latest head. The age is 0 if
+// the head lags behind the config's block.
+func (oc *OCRContractConfigTracker) ConfigAgeBlocks(ctx context.Context) (uint64, error) {
+	raw := oc.getLatestConfigLog()
+	if raw == nil {
+		return 0, ErrNoConfigApplied
+	}
+	head, err := oc.LatestBlockHeight(ctx)
+	if err != nil {
+		return 0, oc.wrapErr(err, "ConfigAgeBlocks could not fetch head")
+	}
+	if head < raw.BlockNumber {
+		return 0, nil
+	}
+	return head - raw.BlockNumber, nil
+}
+
+func (oc *OCRContractConfigTracker) markConfigApplied(digest ocrtypes.ConfigDigest) {
+	atomic.StoreInt64(&oc.lastConfigApplied, oc.clock.Now().UnixNano())
+	labels := map[string]string{"contract_address": oc.contract.Address().Hex()}
+	oc.metrics.IncCounter(MetricConfigsApplied, labels)
+	oc.metrics.SetGauge(MetricSecondsSinceConfigApplied, 0, labels)
+	oc.notifyEventListeners(TrackerEvent{Type: TrackerEventConfigApplied, ConfigDigest: digest})
+}
+
+func (oc *OCRContractConfigTracker) updateSecondsSinceConfigApplied() {
+	last := atomic.LoadInt64(&oc.lastConfigApplied)
+	if last == 0 {
+		return
+	}
+	elapsed := oc.clock.Now().Sub(time.Unix(0, last))
+	oc.metrics.SetGauge(MetricSecondsSinceConfigApplied, elapsed.Seconds(), map[string]string{"contract_address": oc.contract.Address().Hex()})
+}
+
+// runConfigAgeUpdates updates the seconds-since-config-applied gauge every
+// configAgeInterval until the subscription is closed
+func (sub *OCRContractConfigSubscription) runConfigAgeUpdates() {
+	for {
+		select {
+		case <-sub.oc.clock.After(sub.oc.configAgeInterval):
+			sub.oc.updateSecondsSinceConfigApplied()
+		case <-sub.chStop:
+			return
+		}
+	}
+}
+
+// OnNewLongestChain invalidates the LatestConfigDetails cache if the head is
+// at or after the block of the cached config
+func (oc *OCRContractConfigTracker) OnNewLongestChain(_ context.Context, head models.Head) {
+	if oc.detailsCache == nil {
+		return
+	}
+	oc.detailsCache.mu.Lock()
+	defer oc.detailsCache.mu.Unlock()
+	if head.Number >= 0 && uint64(head.Number) >= oc.detailsCache.changedInBlock {
+		oc.detailsCache.valid = false
+		oc.detailsCache.generation++
+	}
+}
+
+func (oc *OCRContractConfigTracker) invalidateConfigDetailsCache() {
+	if oc.detailsCache == nil {
+		return
+	}
+	oc.detailsCache.mu.Lock()
+	defer oc.detailsCache.mu.Unlock()
+	oc.detailsCache.valid = false
+	oc.detailsCache.generation++
+}
+
+func (oc *OCRContractConfigTracker) setConnected(connected bool) {
+	var value uint32
+	if connected {
+		value = 1
+	}
+	if atomic.SwapUint32(&oc.connected, value) == value {
+		return
+	}
+	if connected {
+		oc.notifyEventListeners(TrackerEvent{Type: TrackerEventConnected})
+	} else {
+		oc.notifyEventListeners(TrackerEvent{Type: TrackerEventDisconnected})
+	}
+}
+
+// IsConnected reports whether the log broadcaster is currently connected to
+// the node and delivering logs to the tracker
+func (oc *OCRContractConfigTracker) IsConnected() bool {
+	return atomic.LoadUint32(&oc.connected) == 1
+}
+
+// Pause stops the tracker from acting on logs without closing it. Logs
+// delivered while paused are buffered, up to MaxPausedBroadcasts, and handled
+// in order on Resume.
+func (oc *OCRContractConfigTracker) Pause() {
+	oc.pauseMu.Lock()
+	defer oc.pauseMu.Unlock()
+	oc.paused = true
+}
+
+// Resume handles any logs buffered while paused, in the order they were
+// delivered, and then resumes handling logs as they arrive
+func (oc *OCRContractConfigTracker) Resume() {
+	for {
+		oc.pauseMu.Lock()
+		if len(oc.pausedBroadcasts) == 0 {
+			oc.paused = false
+			oc.pauseMu.Unlock()
+			return
+		}
+		next := oc.pausedBroadcasts[0]
+		oc.pausedBroadcasts = oc.pausedBroadcasts[1:]
+		oc.pauseMu.Unlock()
+
+		next.sub.handleLog(next.lb)
+	}
+}
+
+// bufferIfPaused returns true if the tracker is paused, in which case the
+// broadcast has been buffered for handling on Resume
+func (oc *OCRContractConfigTracker) bufferIfPaused(sub *OCRContractConfigSubscription, lb log.Broadcast) bool {
+	oc.pauseMu.Lock()
+	defer oc.pauseMu.Unlock()
+	if !oc.paused {
+		return false
+	}
+	if len(oc.pausedBroadcasts) >= MaxPausedBroadcasts {
+		oc.logger.Warnw("OCRContract: too many logs buffered while paused, dropping oldest", "limit", MaxPausedBroadcasts)
+		oc.pausedBroadcasts = oc.pausedBroadcasts[1:]
+	}
+	oc.pausedBroadcasts = append(oc.pausedBroadcasts, pausedBroadcast{sub, lb})
+	if len(oc.pausedBroadcasts) == oc.pausedHighWaterMark {
+		oc.logger.Warnw("OCRContract: logs buffered while paused reached high-water mark, logs will be dropped if the tracker is not resumed", "buffered", len(oc.pausedBroadcasts), "limit", MaxPausedBroadcasts)
+		oc.metrics.IncCounter(MetricPausedHighWater, map[string]string{"contract_address": oc.contract.Address().Hex()})
+	}
+	return true
+}
+
+// recordParseResult emits a ParseError event for a failure and counts
+// consecutive failures for WithStrictParsing
+func (oc *OCRContractConfigTracker) recordParseResult(err error) {
+	if err != nil {
+		oc.notifyEventListeners(TrackerEvent{Type: TrackerEventParseError, Err: err})
+	}
+	if oc.strictParsingThreshold == 0 {
+		return
+	}
+	oc.parseFailuresMu.Lock()
+	defer oc.parseFailuresMu.Unlock()
+	if err == nil {
+		oc.consecutiveParseFailures = 0
+		oc.lastParseErr = nil
+		return
+	}
+	oc.consecutiveParseFailures++
+	oc.lastParseErr = err
+}
+
+// RPCErrorRate returns the fraction of the tracker's RPC calls made within
+// the window that failed, or 0 if none were made. Only the most recent
+// rpcOutcomeHistorySize calls are considered, and calls failed fast by an
+// open circuit breaker are not counted.
+func (oc *OCRContractConfigTracker) RPCErrorRate(window time.Duration) float64 {
+	return oc.breaker.errorRate(window)
+}
+
+// Healthy returns an error if the tracker's RPC calls are currently failing
+// fast due to an open circuit breaker or to the contract having no code with
+// WithCodeCheck, or if too many consecutive logs have failed to parse with
+// WithStrictParsing
+func (oc *OCRContractConfigTracker) Healthy() error {
+	if oc.hasNoCode() {
+		return oc.wrapErr(ErrContractHasNoCode, "contract code check failed")
+	}
+	if err := oc.breaker.healthy(); err != nil {
+		return err
+	}
+	oc.parseFailuresMu.Lock()
+	defer oc.parseFailuresMu.Unlock()
+	if oc.strictParsingThreshold > 0 && oc.consecutiveParseFailures >= oc.strictParsingThreshold {
+		return errors.Wrapf(oc.lastParseErr, "%d consecutive logs failed to parse", oc.consecutiveParseFailures)
+	}
+	return nil
+}
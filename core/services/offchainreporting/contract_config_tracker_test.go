@@ -0,0 +1,1544 @@
+package offchainreporting_test
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/gethwrappers/generated/offchain_aggregator_wrapper"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services/eth"
+	ethtestutil "github.com/smartcontractkit/chainlink/core/services/eth/testutil"
+	"github.com/smartcontractkit/chainlink/core/services/log"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/libocr/gethwrappers/offchainaggregator"
+	"github.com/smartcontractkit/libocr/offchainreporting/confighelper"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// fakeClock is a clock whose time only moves on Advance, which fires the
+// channels returned by After that have come due
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []fakeTimer
+	// afters receives the duration of every call to After, see awaitAfter
+	afters chan time.Duration
+}
+
+type fakeTimer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(1600000000, 0), afters: make(chan time.Duration, 1000)}
+}
+
+func (fc *fakeClock) Now() time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.now
+}
+
+func (fc *fakeClock) After(d time.Duration) <-chan time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- fc.now
+	} else {
+		fc.timers = append(fc.timers, fakeTimer{fc.now.Add(d), ch})
+	}
+	select {
+	case fc.afters <- d:
+	default:
+	}
+	return ch
+}
+
+func (fc *fakeClock) Advance(d time.Duration) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.now = fc.now.Add(d)
+	pending := fc.timers[:0]
+	for _, timer := range fc.timers {
+		if timer.at.After(fc.now) {
+			pending = append(pending, timer)
+			continue
+		}
+		timer.ch <- fc.now
+	}
+	fc.timers = pending
+}
+
+// awaitAfter waits for a call to After with the given duration, skipping
+// calls with other durations. Every call is only awaited once, so that the
+// next awaitAfter waits for the next such call.
+func (fc *fakeClock) awaitAfter(t *testing.T, d time.Duration) {
+	t.Helper()
+	for {
+		select {
+		case got := <-fc.afters:
+			if got == d {
+				return
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for a %s timer", d)
+		}
+	}
+}
+
+func mustOffchainAggregatorABI(t *testing.T) abi.ABI {
+	contractABI, err := abi.JSON(strings.NewReader(offchainaggregator.OffchainAggregatorABI))
+	require.NoError(t, err)
+	return contractABI
+}
+
+func mustEncodeLatestConfigDetails(t *testing.T, configCount uint32, blockNumber uint32, digest [16]byte) []byte {
+	b, err := mustOffchainAggregatorABI(t).Methods["latestConfigDetails"].Outputs.Pack(configCount, blockNumber, digest)
+	require.NoError(t, err)
+	return b
+}
+
+func newConfigSetLog(t *testing.T, address common.Address, blockNumber uint64, configCount uint64) types.Log {
+	data, err := mustOffchainAggregatorABI(t).Events["ConfigSet"].Inputs.NonIndexed().Pack(
+		uint32(0),
+		configCount,
+		[]common.Address{common.BigToAddress(big.NewInt(int64(configCount)))},
+		[]common.Address{cltest.NewAddress()},
+		uint8(1),
+		uint64(1),
+		[]byte{1, 2, 3},
+	)
+	require.NoError(t, err)
+	return types.Log{
+		Address:     address,
+		Topics:      []common.Hash{offchainreporting.OCRContractConfigSet},
+		Data:        data,
+		BlockNumber: blockNumber,
+		BlockHash:   cltest.NewHash(),
+		TxHash:      cltest.NewHash(),
+	}
+}
+
+func newRoundRequestedLog(t *testing.T, address common.Address, blockNumber uint64, configDigest ocrtypes.ConfigDigest, epoch uint32, round uint8) types.Log {
+	data, err := mustOffchainAggregatorABI(t).Events["RoundRequested"].Inputs.NonIndexed().Pack(
+		[16]byte(configDigest),
+		epoch,
+		round,
+	)
+	require.NoError(t, err)
+	return types.Log{
+		Address:     address,
+		Topics:      []common.Hash{offchainreporting.OCRContractRoundRequested, cltest.NewAddress().Hash()},
+		Data:        data,
+		BlockNumber: blockNumber,
+		BlockHash:   cltest.NewHash(),
+		TxHash:      cltest.NewHash(),
+	}
+}
+
+func newNewTransmissionLog(t *testing.T, address common.Address, blockNumber uint64, configDigest ocrtypes.ConfigDigest, answer int64, epoch uint32, round uint8, observationCount int) types.Log {
+	observations := make([]*big.Int, observationCount)
+	for i := range observations {
+		observations[i] = big.NewInt(answer)
+	}
+	var reportContext [32]byte
+	copy(reportContext[11:27], configDigest[:])
+	binary.BigEndian.PutUint32(reportContext[27:31], epoch)
+	reportContext[31] = round
+	data, err := mustOffchainAggregatorABI(t).Events["NewTransmission"].Inputs.NonIndexed().Pack(
+		big.NewInt(answer),
+		common.BigToAddress(big.NewInt(int64(epoch))),
+		observations,
+		make([]byte, observationCount),
+		reportContext,
+	)
+	require.NoError(t, err)
+	return types.Log{
+		Address:     address,
+		Topics:      []common.Hash{offchainreporting.OCRContractNewTransmission, common.BigToHash(big.NewInt(int64(epoch)))},
+		Data:        data,
+		BlockNumber: blockNumber,
+		BlockHash:   cltest.NewHash(),
+		TxHash:      cltest.NewHash(),
+	}
+}
+
+func newBroadcast(raw types.Log) *logmocks.Broadcast {
+	lb := new(logmocks.Broadcast)
+	lb.On("RawLog").Return(raw)
+	lb.On("WasAlreadyConsumed").Return(false, nil)
+	lb.On("MarkConsumed").Return(nil)
+	return lb
+}
+
+func newTestTracker(t testing.TB, ethClient *mocks.Client, lb log.Broadcaster, opts ...offchainreporting.OCRContractConfigTrackerOption) (*offchainreporting.OCRContractConfigTracker, common.Address) {
+	address := cltest.NewAddress()
+
+	contract, err := offchain_aggregator_wrapper.NewOffchainAggregator(address, ethClient)
+	require.NoError(t, err)
+	contractCaller, err := offchainaggregator.NewOffchainAggregatorCaller(address, ethClient)
+	require.NoError(t, err)
+
+	tracker, err := offchainreporting.NewOCRContractConfigTracker(
+		contract,
+		contractCaller,
+		ethClient,
+		lb,
+		42,
+		*logger.Default,
+		opts...,
+	)
+	require.NoError(t, err)
+	return tracker, address
+}
+
+// newTestSubscription subscribes to the tracker's configs through the mock
+// log broadcaster. The subscription is closed when the test ends.
+func newTestSubscription(t testing.TB, tracker *offchainreporting.OCRContractConfigTracker, lb *logmocks.Broadcaster) ocrtypes.ContractConfigSubscription {
+	t.Helper()
+	lb.On("Register", mock.Anything, mock.Anything).Return(true)
+	lb.On("Unregister", mock.Anything, mock.Anything).Return()
+	sub, err := tracker.SubscribeToNewConfigs(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(sub.Close)
+	return sub
+}
+
+func Test_OCRContractConfigTracker_ConfigStillCanonical(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, address := newTestTracker(t, ethClient, new(logmocks.Broadcaster))
+
+	canonical, err := tracker.ExportedConfigStillCanonical(context.Background())
+	require.NoError(t, err)
+	require.True(t, canonical)
+
+	configLog := newConfigSetLog(t, address, 42, 1)
+	ethClient.On("FilterLogs", mock.Anything, mock.Anything).Return([]types.Log{configLog}, nil)
+	_, err = tracker.ConfigFromLogs(context.Background(), 42)
+	require.NoError(t, err)
+
+	ethClient.On("HeaderByNumber", mock.Anything, big.NewInt(42)).Return(&models.Head{Number: 42, Hash: configLog.BlockHash}, nil).Once()
+	canonical, err = tracker.ExportedConfigStillCanonical(context.Background())
+	require.NoError(t, err)
+	require.True(t, canonical)
+
+	ethClient.On("HeaderByNumber", mock.Anything, big.NewInt(42)).Return(&models.Head{Number: 42, Hash: cltest.NewHash()}, nil).Once()
+	canonical, err = tracker.ExportedConfigStillCanonical(context.Background())
+	require.NoError(t, err)
+	require.False(t, canonical)
+
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_ConfigDetailsCache(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithConfigDetailsCache())
+
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 1, 42, [16]byte{1}), nil).Once()
+	for i := 0; i < 3; i++ {
+		changedInBlock, _, err := tracker.LatestConfigDetails(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(42), changedInBlock)
+	}
+	ethClient.AssertExpectations(t)
+
+	sub := newTestSubscription(t, tracker, lb)
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 43, 2)), nil)
+
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 2, 43, [16]byte{2}), nil).Once()
+	changedInBlock, _, err := tracker.LatestConfigDetails(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(43), changedInBlock)
+
+	tracker.OnNewLongestChain(context.Background(), models.Head{Number: 44})
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 2, 43, [16]byte{2}), nil).Once()
+	_, _, err = tracker.LatestConfigDetails(context.Background())
+	require.NoError(t, err)
+
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_ConfigDetailsCache_ConcurrentMisses(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, _ := newTestTracker(t, ethClient, new(logmocks.Broadcaster), offchainreporting.WithConfigDetailsCache())
+
+	// Concurrent misses share a single call
+	started, release := make(chan struct{}), make(chan struct{})
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 1, 42, [16]byte{1}), nil).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Once()
+	var wg sync.WaitGroup
+	fetch := func() {
+		defer wg.Done()
+		changedInBlock, _, err := tracker.LatestConfigDetails(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(42), changedInBlock)
+	}
+	wg.Add(1)
+	go fetch()
+	<-started
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go fetch()
+	}
+	close(release)
+	wg.Wait()
+	ethClient.AssertExpectations(t)
+
+	// The cache is not locked during the call, and a result fetched across an
+	// invalidation is not cached
+	tracker.OnNewLongestChain(context.Background(), models.Head{Number: 43})
+	started, release = make(chan struct{}), make(chan struct{})
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 1, 42, [16]byte{1}), nil).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Once()
+	wg.Add(1)
+	go fetch()
+	<-started
+	tracker.OnNewLongestChain(context.Background(), models.Head{Number: 44})
+	close(release)
+	wg.Wait()
+
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 1, 42, [16]byte{1}), nil).Once()
+	_, _, err := tracker.LatestConfigDetails(context.Background())
+	require.NoError(t, err)
+	ethClient.AssertExpectations(t)
+
+	// A waiter gives up with its own context
+	tracker.OnNewLongestChain(context.Background(), models.Head{Number: 45})
+	started, release = make(chan struct{}), make(chan struct{})
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 1, 42, [16]byte{1}), nil).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Once()
+	wg.Add(1)
+	go fetch()
+	<-started
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = tracker.LatestConfigDetails(ctx)
+	require.Equal(t, context.Canceled, errors.Cause(err))
+	close(release)
+	wg.Wait()
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_ReadOnly(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly())
+
+	sub := newTestSubscription(t, tracker, lb)
+	require.Nil(t, sub.Configs())
+
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 10, 1)), nil)
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 20, 2)), nil)
+
+	history := tracker.ConfigHistory()
+	require.Len(t, history, 2)
+	require.Equal(t, uint64(10), history[0].BlockNumber)
+	require.Equal(t, common.BigToAddress(big.NewInt(1)), history[0].Signers[0])
+	require.Equal(t, uint64(20), history[1].BlockNumber)
+	require.Equal(t, common.BigToAddress(big.NewInt(2)), history[1].Signers[0])
+}
+
+func Test_NewOCRContractConfigTracker_Defaults(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb)
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	// Configs are delivered, not merely recorded, and only the latest round
+	// request is kept
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 1, 1)), nil)
+	sub.(log.Listener).HandleLog(newBroadcast(newRoundRequestedLog(t, address, 2, ocrtypes.ConfigDigest{1}, 1, 1)), nil)
+	sub.(log.Listener).HandleLog(newBroadcast(newRoundRequestedLog(t, address, 3, ocrtypes.ConfigDigest{1}, 2, 1)), nil)
+	cc := <-sub.Configs()
+	require.Equal(t, common.BigToAddress(big.NewInt(1)), cc.Signers[0])
+	require.Empty(t, tracker.ConfigHistory())
+	history := tracker.RoundRequestHistory()
+	require.Len(t, history, 1)
+	require.Equal(t, uint64(3), history[0].BlockNumber)
+	require.NoError(t, tracker.Healthy())
+}
+
+func Test_NewOCRContractConfigTracker_CombinedOptions(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	sink := &fakeMetricsSink{}
+	clock := newFakeClock()
+	tracker, address := newTestTracker(t, ethClient, lb,
+		offchainreporting.WithReadOnly(),
+		offchainreporting.WithClock(clock),
+		offchainreporting.WithMetricsSink(sink),
+		offchainreporting.WithRoundRequestHistory(1),
+		offchainreporting.WithCircuitBreaker(1, time.Minute),
+	)
+
+	sub := newTestSubscription(t, tracker, lb)
+	require.Nil(t, sub.Configs())
+
+	listener := sub.(log.Listener)
+	listener.HandleLog(newBroadcast(newConfigSetLog(t, address, 1, 1)), nil)
+	listener.HandleLog(newBroadcast(newRoundRequestedLog(t, address, 2, ocrtypes.ConfigDigest{1}, 1, 1)), nil)
+	listener.HandleLog(newBroadcast(newRoundRequestedLog(t, address, 3, ocrtypes.ConfigDigest{1}, 1, 2)), nil)
+
+	require.Len(t, tracker.ConfigHistory(), 1)
+	history := tracker.RoundRequestHistory()
+	require.Len(t, history, 1)
+	require.Equal(t, uint64(3), history[0].BlockNumber)
+
+	labels := map[string]string{"contract_address": address.Hex()}
+	require.Equal(t, []fakeMetric{
+		{offchainreporting.MetricConfigsApplied, 1, labels},
+		{offchainreporting.MetricSecondsSinceConfigApplied, 0, labels},
+	}, sink.metrics)
+
+	// A single failure opens the breaker until the clock passes the cooldown
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(nil, errors.New("rpc down")).Once()
+	_, err := tracker.LatestBlockHeight(context.Background())
+	require.EqualError(t, errors.Cause(err), "rpc down")
+	_, err = tracker.LatestBlockHeight(context.Background())
+	require.Equal(t, offchainreporting.ErrCircuitOpen, errors.Cause(err))
+	clock.Advance(time.Minute)
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(&models.Head{Number: 42}, nil).Once()
+	height, err := tracker.LatestBlockHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), height)
+
+	ethClient.AssertExpectations(t)
+}
+
+func Test_NewOCRContractConfigTrackerChecked(t *testing.T) {
+	address := cltest.NewAddress()
+	ethClient := new(mocks.Client)
+	contract, err := offchain_aggregator_wrapper.NewOffchainAggregator(address, ethClient)
+	require.NoError(t, err)
+	contractCaller, err := offchainaggregator.NewOffchainAggregatorCaller(address, ethClient)
+	require.NoError(t, err)
+
+	newChecked := func() (*offchainreporting.OCRContractConfigTracker, error) {
+		return offchainreporting.NewOCRContractConfigTrackerChecked(context.Background(), contract, contractCaller, ethClient, new(logmocks.Broadcaster), 42, *logger.Default)
+	}
+
+	ethClient.On("CodeAt", mock.Anything, address, (*big.Int)(nil)).Return([]byte{}, nil).Once()
+	_, err = newChecked()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no OffchainAggregator at address")
+
+	ethClient.On("CodeAt", mock.Anything, address, (*big.Int)(nil)).Return([]byte{1, 2, 3}, nil).Once()
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 1, 42, [16]byte{1}), nil).Once()
+	tracker, err := newChecked()
+	require.NoError(t, err)
+	require.NotNil(t, tracker)
+
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_NilContractCaller(t *testing.T) {
+	address := cltest.NewAddress()
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	contract, err := offchain_aggregator_wrapper.NewOffchainAggregator(address, ethClient)
+	require.NoError(t, err)
+
+	tracker, err := offchainreporting.NewOCRContractConfigTracker(contract, nil, ethClient, lb, 42, *logger.Default,
+		offchainreporting.WithReadOnly(), offchainreporting.WithConfigDigestVerification())
+	require.NoError(t, err)
+
+	t.Run("LatestConfigDetails", func(t *testing.T) {
+		_, _, err := tracker.LatestConfigDetails(context.Background())
+		require.Equal(t, offchainreporting.ErrNoContractCaller, err)
+	})
+
+	t.Run("config digest verification", func(t *testing.T) {
+		sub := newTestSubscription(t, tracker, lb)
+
+		broadcast := new(logmocks.Broadcast)
+		broadcast.On("RawLog").Return(newConfigSetLog(t, address, 1, 1))
+		broadcast.On("WasAlreadyConsumed").Return(false, nil)
+		sub.(log.Listener).HandleLog(broadcast, nil)
+		broadcast.AssertNotCalled(t, "MarkConsumed")
+		require.Empty(t, tracker.ConfigHistory())
+	})
+
+	t.Run("NewOCRContractConfigTrackerChecked", func(t *testing.T) {
+		ethClient.On("CodeAt", mock.Anything, address, (*big.Int)(nil)).Return([]byte{1, 2, 3}, nil).Once()
+		_, err := offchainreporting.NewOCRContractConfigTrackerChecked(context.Background(), contract, nil, ethClient, lb, 42, *logger.Default)
+		require.Error(t, err)
+		require.Equal(t, offchainreporting.ErrNoContractCaller, errors.Cause(err))
+	})
+
+	// None of the above reached the RPC
+	ethClient.AssertNotCalled(t, "CallContract", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// mockBatchHeadAndConfig answers the batched head and latestConfigDetails
+// request with the given head and config block
+func mockBatchHeadAndConfig(t *testing.T, ethClient *mocks.Client, head int64, changedInBlock uint32) {
+	ethClient.On("BatchCallContext", mock.Anything, mock.MatchedBy(func(b []rpc.BatchElem) bool {
+		return len(b) == 2 && b[0].Method == "eth_getBlockByNumber" && b[1].Method == "eth_call"
+	})).Return(nil).Run(func(args mock.Arguments) {
+		b := args.Get(1).([]rpc.BatchElem)
+		*b[0].Result.(**models.Head) = &models.Head{Number: head}
+		*b[1].Result.(*hexutil.Bytes) = mustEncodeLatestConfigDetails(t, 1, changedInBlock, [16]byte{1})
+	}).Once()
+}
+
+func Test_OCRContractConfigTracker_CatchUpOnSubscribe(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithCatchUpOnSubscribe(10))
+
+	// A config was set at block 95 while the node was down
+	mockBatchHeadAndConfig(t, ethClient, 100, 95)
+	ethClient.On("FilterLogs", mock.Anything, mock.MatchedBy(func(q ethereum.FilterQuery) bool {
+		return q.FromBlock.Int64() == 90 && q.ToBlock.Int64() == 100
+	})).Return([]types.Log{newConfigSetLog(t, address, 95, 7)}, nil)
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	select {
+	case cc := <-sub.Configs():
+		require.Equal(t, common.BigToAddress(big.NewInt(7)), cc.Signers[0])
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for caught up config")
+	}
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_IsConnected(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, _ := newTestTracker(t, ethClient, lb)
+	require.False(t, tracker.IsConnected())
+
+	sub := newTestSubscription(t, tracker, lb)
+	require.True(t, tracker.IsConnected())
+
+	listener := sub.(log.Listener)
+	listener.OnDisconnect()
+	require.False(t, tracker.IsConnected())
+
+	// No config has been seen, so reconnecting makes no RPC calls
+	listener.OnConnect()
+	require.True(t, tracker.IsConnected())
+}
+
+func Test_OCRContractConfigTracker_SubscribeToNewConfigs_RegisterFails(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, _ := newTestTracker(t, ethClient, lb)
+
+	lb.On("Register", mock.Anything, mock.Anything).Return(false)
+	_, err := tracker.SubscribeToNewConfigs(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to register with logBroadcaster")
+	require.False(t, tracker.IsConnected())
+}
+
+func Test_OCRContractConfigTracker_PauseResume(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly())
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	tracker.Pause()
+	var broadcasts []*logmocks.Broadcast
+	for i := uint64(1); i <= 3; i++ {
+		broadcast := newBroadcast(newConfigSetLog(t, address, i, i))
+		broadcasts = append(broadcasts, broadcast)
+		sub.(log.Listener).HandleLog(broadcast, nil)
+	}
+	require.Len(t, tracker.ConfigHistory(), 0)
+	for _, broadcast := range broadcasts {
+		broadcast.AssertNotCalled(t, "WasAlreadyConsumed")
+		broadcast.AssertNotCalled(t, "MarkConsumed")
+	}
+
+	tracker.Resume()
+	history := tracker.ConfigHistory()
+	require.Len(t, history, 3)
+	for i, config := range history {
+		require.Equal(t, uint64(i+1), config.BlockNumber)
+		broadcasts[i].AssertCalled(t, "MarkConsumed")
+	}
+
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 4, 4)), nil)
+	require.Len(t, tracker.ConfigHistory(), 4)
+}
+
+func Test_OCRContractConfigTracker_SubscribeReorgs(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly())
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	reorgs, unsubscribe := tracker.SubscribeReorgs()
+	defer unsubscribe()
+
+	raw := newConfigSetLog(t, address, 7, 1)
+	cc, err := offchainreporting.ParseOCRConfigSet(raw)
+	require.NoError(t, err)
+	raw.Removed = true
+	broadcast := newBroadcast(raw)
+	sub.(log.Listener).HandleLog(broadcast, nil)
+
+	select {
+	case event := <-reorgs:
+		require.Equal(t, "ConfigSet", event.Event)
+		require.Equal(t, cc.ConfigDigest, event.ConfigDigest)
+		require.Equal(t, uint64(7), event.BlockNumber)
+		require.Equal(t, raw.BlockHash, event.BlockHash)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reorg event")
+	}
+	// The removed config is not applied
+	require.Len(t, tracker.ConfigHistory(), 0)
+	broadcast.AssertCalled(t, "MarkConsumed")
+}
+
+// topicFilteringBroadcaster is a broadcaster that only delivers the given
+// topics
+type topicFilteringBroadcaster struct {
+	*logmocks.Broadcaster
+	topics []common.Hash
+}
+
+func (b topicFilteringBroadcaster) SubscribedTopics(common.Address) []common.Hash {
+	return b.topics
+}
+
+func Test_OCRContractConfigTracker_SubscribedTopicsCheck(t *testing.T) {
+	t.Run("missing a tracked topic", func(t *testing.T) {
+		lb := topicFilteringBroadcaster{new(logmocks.Broadcaster), []common.Hash{offchainreporting.OCRContractConfigSet}}
+		tracker, _ := newTestTracker(t, new(mocks.Client), lb, offchainreporting.WithReadOnly())
+
+		_, err := tracker.SubscribeToNewConfigs(context.Background())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), offchainreporting.OCRContractRoundRequested.Hex())
+		lb.AssertNotCalled(t, "Register", mock.Anything, mock.Anything)
+	})
+
+	t.Run("all tracked topics", func(t *testing.T) {
+		lb := topicFilteringBroadcaster{new(logmocks.Broadcaster), offchainreporting.OCRTrackedTopics()}
+		lb.On("Register", mock.Anything, mock.Anything).Return(true)
+		lb.On("Unregister", mock.Anything, mock.Anything).Return()
+		tracker, _ := newTestTracker(t, new(mocks.Client), lb, offchainreporting.WithReadOnly())
+
+		sub, err := tracker.SubscribeToNewConfigs(context.Background())
+		require.NoError(t, err)
+		sub.Close()
+	})
+}
+
+func Test_OCRContractConfigTracker_PausedHighWaterMark(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	address := cltest.NewAddress()
+	contract, err := offchain_aggregator_wrapper.NewOffchainAggregator(address, ethClient)
+	require.NoError(t, err)
+
+	core, logs := observer.New(zapcore.WarnLevel)
+	sink := &fakeMetricsSink{}
+	tracker, err := offchainreporting.NewOCRContractConfigTracker(contract, nil, ethClient, lb, 42,
+		logger.Logger{SugaredLogger: zap.New(core).Sugar()},
+		offchainreporting.WithReadOnly(),
+		offchainreporting.WithMetricsSink(sink),
+		offchainreporting.WithPausedHighWaterMark(3),
+	)
+	require.NoError(t, err)
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	countWarnings := func() int {
+		return logs.FilterMessageSnippet("reached high-water mark").Len()
+	}
+
+	tracker.Pause()
+	for i := uint64(1); i <= 2; i++ {
+		sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, i, i)), nil)
+	}
+	require.Equal(t, 0, countWarnings())
+
+	// Warns once on crossing the mark, not for every log buffered past it
+	for i := uint64(3); i <= 5; i++ {
+		sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, i, i)), nil)
+	}
+	require.Equal(t, 1, countWarnings())
+	require.Equal(t, []fakeMetric{
+		{offchainreporting.MetricPausedHighWater, 1, map[string]string{"contract_address": address.Hex()}},
+	}, sink.metrics)
+
+	tracker.Resume()
+	require.Len(t, tracker.ConfigHistory(), 5)
+}
+
+func Test_OCRContractConfigTracker_RPCSemaphore(t *testing.T) {
+	ethClient := new(mocks.Client)
+	sem := make(chan struct{}, 1)
+	tracker, _ := newTestTracker(t, ethClient, new(logmocks.Broadcaster), offchainreporting.WithRPCSemaphore(sem))
+
+	// Each call is held until released, so a call admitted while another is
+	// in flight would be counted
+	var inFlight, maxInFlight int32
+	entered, release := make(chan struct{}, 3), make(chan struct{})
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(&models.Head{Number: 42}, nil).Run(func(mock.Arguments) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		entered <- struct{}{}
+		<-release
+		atomic.AddInt32(&inFlight, -1)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := tracker.LatestBlockHeight(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		<-entered
+		require.Len(t, sem, 1)
+		release <- struct{}{}
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), atomic.LoadInt32(&maxInFlight))
+	require.Len(t, sem, 0)
+
+	// Waiting for the semaphore respects the context
+	sem <- struct{}{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := tracker.LatestBlockHeight(ctx)
+	require.Equal(t, context.Canceled, errors.Cause(err))
+}
+
+func Test_OCRContractConfigTracker_ConfigDetailsAt(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, _ := newTestTracker(t, ethClient, new(logmocks.Broadcaster))
+
+	ethClient.On("CallContract", mock.Anything, mock.Anything, big.NewInt(100)).Return(mustEncodeLatestConfigDetails(t, 2, 90, [16]byte{2}), nil).Once()
+
+	changedInBlock, digest, err := tracker.ConfigDetailsAt(context.Background(), 100)
+	require.NoError(t, err)
+	require.Equal(t, uint64(90), changedInBlock)
+	require.Equal(t, ocrtypes.ConfigDigest{2}, digest)
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_ErrorsIdentifyTracker(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, address := newTestTracker(t, ethClient, new(logmocks.Broadcaster))
+
+	ethClient.On("FilterLogs", mock.Anything, mock.Anything).Return(nil, errors.New("rpc down")).Once()
+	_, err := tracker.ConfigFromLogs(context.Background(), 42)
+	require.Error(t, err)
+	require.Equal(t, "rpc down", errors.Cause(err).Error())
+	require.Contains(t, err.Error(), "jobID 42")
+	require.Contains(t, err.Error(), address.Hex())
+}
+
+func Test_OCRContractConfigTracker_CurrentOracles(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, address := newTestTracker(t, ethClient, new(logmocks.Broadcaster))
+
+	_, _, err := tracker.CurrentOracles()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no config has been seen yet")
+
+	ethClient.On("FilterLogs", mock.Anything, mock.Anything).Return([]types.Log{newConfigSetLog(t, address, 42, 3)}, nil)
+	cc, err := tracker.ConfigFromLogs(context.Background(), 42)
+	require.NoError(t, err)
+
+	signers, transmitters, err := tracker.CurrentOracles()
+	require.NoError(t, err)
+	require.Equal(t, []common.Address{common.BigToAddress(big.NewInt(3))}, signers)
+	require.Equal(t, cc.Transmitters, transmitters)
+}
+
+func Test_OCRContractConfigTracker_WaitForConfigDigest(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly())
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	contractFilterer, err := offchainaggregator.NewOffchainAggregatorFilterer(address, ethClient)
+	require.NoError(t, err)
+	first := newConfigSetLog(t, address, 1, 1)
+	second := newConfigSetLog(t, address, 2, 2)
+	configSet, err := contractFilterer.ParseConfigSet(second)
+	require.NoError(t, err)
+	digest := confighelper.ContractConfigFromConfigSetEvent(*configSet).ConfigDigest
+
+	// Another config being applied does not satisfy the wait, which only
+	// gives up with its context
+	sub.(log.Listener).HandleLog(newBroadcast(first), nil)
+	canceledCtx, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	require.Equal(t, context.Canceled, tracker.WaitForConfigDigest(canceledCtx, digest))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	chErr := make(chan error)
+	go func() {
+		chErr <- tracker.WaitForConfigDigest(ctx, digest)
+	}()
+	sub.(log.Listener).HandleLog(newBroadcast(second), nil)
+	require.NoError(t, <-chErr)
+
+	// A digest already applied is returned straight away
+	require.NoError(t, tracker.WaitForConfigDigest(context.Background(), digest))
+}
+
+func Test_OCRContractConfigTracker_CanonicalLogs(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, address := newTestTracker(t, ethClient, new(logmocks.Broadcaster), offchainreporting.WithCanonicalLogs())
+
+	// The block is reorged between the filter call and verification, so the
+	// log from the orphaned block is replaced with the one from the new block
+	orphaned := newConfigSetLog(t, address, 42, 1)
+	canonical := newConfigSetLog(t, address, 42, 2)
+	ethClient.On("FilterLogs", mock.Anything, mock.Anything).Return([]types.Log{orphaned}, nil).Once()
+	ethClient.On("FilterLogs", mock.Anything, mock.Anything).Return([]types.Log{canonical}, nil).Once()
+	ethClient.On("HeaderByNumber", mock.Anything, big.NewInt(42)).Return(&models.Head{Number: 42, Hash: canonical.BlockHash}, nil)
+
+	cc, err := tracker.ConfigFromLogs(context.Background(), 42)
+	require.NoError(t, err)
+	require.Equal(t, common.BigToAddress(big.NewInt(2)), cc.Signers[0])
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_EagerInitialFetch(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithEagerInitialFetch())
+
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 3, 42, [16]byte{3}), nil).Once()
+	ethClient.On("FilterLogs", mock.Anything, mock.Anything).Return([]types.Log{newConfigSetLog(t, address, 42, 3)}, nil).Once()
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	select {
+	case cc := <-sub.Configs():
+		require.Equal(t, common.BigToAddress(big.NewInt(3)), cc.Signers[0])
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for initial config")
+	}
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_StartupDelay(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	clock := cltest.NewTriggerClock(t)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithEagerInitialFetch(), offchainreporting.WithStartupDelay(time.Minute), offchainreporting.WithClock(clock))
+
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 3, 42, [16]byte{3}), nil).Once()
+	ethClient.On("FilterLogs", mock.Anything, mock.Anything).Return([]types.Log{newConfigSetLog(t, address, 42, 3)}, nil).Once()
+
+	// Subscribing does not wait out the delay
+	sub := newTestSubscription(t, tracker, lb)
+	ethClient.AssertNotCalled(t, "CallContract", mock.Anything, mock.Anything, mock.Anything)
+	ethClient.AssertNotCalled(t, "FilterLogs", mock.Anything, mock.Anything)
+
+	clock.Trigger()
+	select {
+	case cc := <-sub.Configs():
+		require.Equal(t, common.BigToAddress(big.NewInt(3)), cc.Signers[0])
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for initial config")
+	}
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_StartupDelay_Closed(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, _ := newTestTracker(t, ethClient, lb, offchainreporting.WithEagerInitialFetch(), offchainreporting.WithStartupDelay(time.Minute), offchainreporting.WithClock(cltest.NewTriggerClock(t)))
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	// Close waits for the initial fetch, which gives up during the delay
+	sub.Close()
+	ethClient.AssertNotCalled(t, "CallContract", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_OCRContractConfigTracker_SecondsSinceConfigApplied(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	clock := newFakeClock()
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly(), offchainreporting.WithClock(clock), offchainreporting.WithConfigAgeUpdates(time.Second))
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 1, 1)), nil)
+	require.Equal(t, float64(1), testutil.ToFloat64(offchainreporting.PromOCRTrackerConfigsApplied.WithLabelValues(address.Hex())))
+	gauge := offchainreporting.PromOCRTrackerSecondsSinceConfigApplied.WithLabelValues(address.Hex())
+	require.Equal(t, float64(0), testutil.ToFloat64(gauge))
+
+	// The gauge has been updated once the ticker waits for its next tick
+	clock.awaitAfter(t, time.Second)
+	clock.Advance(90 * time.Second)
+	clock.awaitAfter(t, time.Second)
+	require.Equal(t, float64(90), testutil.ToFloat64(gauge))
+}
+
+func Test_OCRContractConfigTracker_RoundRequestHistory(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithRoundRequestHistory(3))
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	for i := uint64(1); i <= 5; i++ {
+		broadcast := newBroadcast(newRoundRequestedLog(t, address, i, ocrtypes.ConfigDigest{1}, uint32(i), 1))
+		sub.(log.Listener).HandleLog(broadcast, nil)
+		broadcast.AssertCalled(t, "MarkConsumed")
+	}
+
+	history := tracker.RoundRequestHistory()
+	require.Len(t, history, 3)
+	for i, rr := range history {
+		require.Equal(t, uint64(i+3), rr.BlockNumber)
+		require.Equal(t, uint32(i+3), rr.Epoch)
+	}
+}
+
+func Test_OCRContractConfigTracker_RoundRequestHistory_IgnoresDuplicates(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithRoundRequestHistory(3))
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	raw := newRoundRequestedLog(t, address, 1, ocrtypes.ConfigDigest{1}, 1, 1)
+	sub.(log.Listener).HandleLog(newBroadcast(raw), nil)
+	history := tracker.RoundRequestHistory()
+	require.Len(t, history, 1)
+
+	duplicate := newBroadcast(raw)
+	sub.(log.Listener).HandleLog(duplicate, nil)
+	duplicate.AssertCalled(t, "MarkConsumed")
+	require.Equal(t, history, tracker.RoundRequestHistory())
+
+	// Another log in the same block is recorded
+	sameBlock := raw
+	sameBlock.Index = 1
+	sub.(log.Listener).HandleLog(newBroadcast(sameBlock), nil)
+	require.Len(t, tracker.RoundRequestHistory(), 2)
+
+	// The same position in a block that reorged the first one replaces it
+	reorged := newRoundRequestedLog(t, address, 1, ocrtypes.ConfigDigest{1}, 2, 1)
+	sub.(log.Listener).HandleLog(newBroadcast(reorged), nil)
+	history = tracker.RoundRequestHistory()
+	require.Len(t, history, 2)
+	require.Equal(t, reorged.BlockHash, history[0].Raw.BlockHash)
+	require.Equal(t, uint32(2), history[0].Epoch)
+	require.Equal(t, sameBlock.BlockHash, history[1].Raw.BlockHash)
+}
+
+func Test_OCRContractConfigTracker_StrictParsing(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly(), offchainreporting.WithStrictParsing(3))
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	malformed := types.Log{Address: address, Topics: []common.Hash{offchainreporting.OCRContractConfigSet}, Data: []byte{1, 2, 3}}
+	ethClient.On("TransactionReceipt", mock.Anything, malformed.TxHash).Return(&types.Receipt{Logs: []*types.Log{&malformed}}, nil)
+	for i := 0; i < 2; i++ {
+		sub.(log.Listener).HandleLog(newBroadcast(malformed), nil)
+		require.NoError(t, tracker.Healthy())
+	}
+	sub.(log.Listener).HandleLog(newBroadcast(malformed), nil)
+	require.Error(t, tracker.Healthy())
+	require.Contains(t, tracker.Healthy().Error(), "3 consecutive logs failed to parse")
+
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 1, 1)), nil)
+	require.NoError(t, tracker.Healthy())
+}
+
+func Test_OCRContractConfigTracker_Subscribe_Ordering(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly())
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	const numConfigs = 40
+	fast, unsubscribeFast := tracker.Subscribe()
+	slow, unsubscribeSlow := tracker.Subscribe()
+	defer unsubscribeSlow()
+
+	var fastSeen []int64
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for cc := range fast {
+			fastSeen = append(fastSeen, cc.Signers[0].Hash().Big().Int64())
+			if fastSeen[len(fastSeen)-1] == numConfigs {
+				return
+			}
+		}
+	}()
+
+	for i := uint64(1); i <= numConfigs; i++ {
+		sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, i, i)), nil)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the fast subscriber")
+	}
+	unsubscribeFast()
+
+	var slowSeen []int64
+	for len(slow) > 0 {
+		slowSeen = append(slowSeen, (<-slow).Signers[0].Hash().Big().Int64())
+	}
+
+	for _, seen := range [][]int64{fastSeen, slowSeen} {
+		require.NotEmpty(t, seen)
+		require.True(t, sort.SliceIsSorted(seen, func(i, j int) bool { return seen[i] < seen[j] }), "configs out of order: %v", seen)
+		for i := 1; i < len(seen); i++ {
+			require.NotEqual(t, seen[i-1], seen[i], "config delivered twice: %v", seen)
+		}
+		require.Equal(t, int64(numConfigs), seen[len(seen)-1])
+	}
+	// The slow subscriber missed the oldest configs
+	require.Len(t, slowSeen, 16)
+}
+
+func Test_OCRContractConfigTracker_SubscribeWithSnapshot(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly())
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	configs, unsubscribe := tracker.SubscribeWithSnapshot()
+	select {
+	case cc := <-configs:
+		t.Fatalf("unexpected snapshot before any config was applied: %v", cc)
+	default:
+	}
+	unsubscribe()
+
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 1, 1)), nil)
+
+	configs, unsubscribe = tracker.SubscribeWithSnapshot()
+	defer unsubscribe()
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 2, 2)), nil)
+
+	require.Equal(t, common.BigToAddress(big.NewInt(1)), (<-configs).Signers[0])
+	require.Equal(t, common.BigToAddress(big.NewInt(2)), (<-configs).Signers[0])
+}
+
+func Test_OCRContractConfigTracker_LatestRoundRequested(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly(), offchainreporting.WithRoundRequestHistory(10))
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	_, found := tracker.LatestRoundRequested()
+	require.False(t, found)
+
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 1, 1)), nil)
+	digest := tracker.ConfigHistory()[0].ConfigDigest
+	sub.(log.Listener).HandleLog(newBroadcast(newRoundRequestedLog(t, address, 2, digest, 3, 1)), nil)
+
+	rr, found := tracker.LatestRoundRequested()
+	require.True(t, found)
+	require.Equal(t, uint32(3), rr.Epoch)
+
+	// The round was requested for the old config
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 3, 2)), nil)
+	_, found = tracker.LatestRoundRequested()
+	require.False(t, found)
+	require.Len(t, tracker.RoundRequestHistory(), 1)
+}
+
+func Test_OCRContractConfigTracker_LatestRoundRequested_DefaultOptions(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly())
+	events, unsubscribe := tracker.SubscribeEvents()
+	defer unsubscribe()
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 1, 1)), nil)
+	digest := tracker.ConfigHistory()[0].ConfigDigest
+	sub.(log.Listener).HandleLog(newBroadcast(newRoundRequestedLog(t, address, 2, digest, 3, 1)), nil)
+
+	rr, found := tracker.LatestRoundRequested()
+	require.True(t, found)
+	require.Equal(t, uint32(3), rr.Epoch)
+	_, found = tracker.LatestRoundRequester()
+	require.True(t, found)
+	epoch, stuck := tracker.ExpectedNextEpoch()
+	require.Equal(t, uint32(3), epoch)
+	require.False(t, stuck)
+
+	var seen []offchainreporting.TrackerEventType
+	for len(events) > 0 {
+		seen = append(seen, (<-events).Type)
+	}
+	require.Contains(t, seen, offchainreporting.TrackerEventRoundRequested)
+}
+
+func Test_OCRContractConfigTracker_LatestRoundRequester(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly(), offchainreporting.WithRoundRequestHistory(10))
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	_, found := tracker.LatestRoundRequester()
+	require.False(t, found)
+
+	requester := cltest.NewAddress()
+	raw := newRoundRequestedLog(t, address, 2, ocrtypes.ConfigDigest{1}, 3, 1)
+	raw.Topics[1] = requester.Hash()
+	sub.(log.Listener).HandleLog(newBroadcast(raw), nil)
+
+	got, found := tracker.LatestRoundRequester()
+	require.True(t, found)
+	require.Equal(t, requester, got)
+}
+
+func Test_OCRContractConfigTracker_ReconcileLatestRoundRequested(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly(), offchainreporting.WithRoundRequestHistory(10))
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	// The request in block 1900 was reorged out and the requests in blocks
+	// 1500 and 1800 were missed while disconnected
+	digest := ocrtypes.ConfigDigest{1}
+	sub.(log.Listener).HandleLog(newBroadcast(newRoundRequestedLog(t, address, 5, digest, 1, 1)), nil)
+	sub.(log.Listener).HandleLog(newBroadcast(newRoundRequestedLog(t, address, 1900, digest, 9, 1)), nil)
+	rr, found := tracker.LatestRoundRequested()
+	require.True(t, found)
+	require.Equal(t, uint32(9), rr.Epoch)
+
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(&models.Head{Number: 2000}, nil).Once()
+	ethClient.On("FilterLogs", mock.Anything, mock.MatchedBy(func(q ethereum.FilterQuery) bool {
+		return q.FromBlock.Int64() == 1000 && q.ToBlock.Int64() == 2000 && q.Topics[0][0] == offchainreporting.OCRContractRoundRequested
+	})).Return([]types.Log{
+		newRoundRequestedLog(t, address, 1500, digest, 6, 1),
+		newRoundRequestedLog(t, address, 1800, digest, 7, 1),
+	}, nil).Once()
+
+	require.NoError(t, tracker.ReconcileLatestRoundRequested(context.Background()))
+
+	rr, found = tracker.LatestRoundRequested()
+	require.True(t, found)
+	require.Equal(t, uint32(7), rr.Epoch)
+	require.Equal(t, uint64(1800), rr.BlockNumber)
+	history := tracker.RoundRequestHistory()
+	require.Len(t, history, 3)
+	require.Equal(t, uint64(5), history[0].BlockNumber)
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_ReconcileLatestRoundRequested_NoHistory(t *testing.T) {
+	tracker, _ := newTestTracker(t, new(mocks.Client), new(logmocks.Broadcaster))
+	require.Error(t, tracker.ReconcileLatestRoundRequested(context.Background()))
+}
+
+func Test_OCRContractConfigTracker_LatestTransmission(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly())
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	_, found := tracker.LatestTransmission()
+	require.False(t, found)
+
+	broadcast := newBroadcast(newNewTransmissionLog(t, address, 5, ocrtypes.ConfigDigest{1}, 1234, 3, 2, 4))
+	sub.(log.Listener).HandleLog(broadcast, nil)
+	broadcast.AssertCalled(t, "MarkConsumed")
+
+	transmission, found := tracker.LatestTransmission()
+	require.True(t, found)
+	require.Equal(t, uint32(3), transmission.AggregatorRoundID)
+	require.Equal(t, big.NewInt(1234), transmission.Answer)
+	require.Equal(t, common.BigToAddress(big.NewInt(3)), transmission.Transmitter)
+	require.Equal(t, ocrtypes.ConfigDigest{1}, transmission.ConfigDigest)
+	require.Equal(t, uint32(3), transmission.Epoch)
+	require.Equal(t, uint8(2), transmission.Round)
+	require.Equal(t, 4, transmission.ObservationCount)
+	require.Equal(t, uint64(5), transmission.BlockNumber)
+
+	// An older transmission delivered late does not replace the latest
+	sub.(log.Listener).HandleLog(newBroadcast(newNewTransmissionLog(t, address, 4, ocrtypes.ConfigDigest{1}, 1000, 2, 1, 4)), nil)
+	transmission, found = tracker.LatestTransmission()
+	require.True(t, found)
+	require.Equal(t, uint32(3), transmission.Epoch)
+}
+
+func Test_OCRContractConfigTracker_ExpectedNextEpoch(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	clock := newFakeClock()
+	tracker, address := newTestTracker(t, ethClient, lb,
+		offchainreporting.WithReadOnly(),
+		offchainreporting.WithClock(clock),
+		offchainreporting.WithRoundRequestHistory(10),
+		offchainreporting.WithStuckRoundTimeout(time.Minute),
+	)
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	epoch, stuck := tracker.ExpectedNextEpoch()
+	require.Equal(t, uint32(0), epoch)
+	require.False(t, stuck)
+
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 1, 1)), nil)
+	digest := tracker.ConfigHistory()[0].ConfigDigest
+	sub.(log.Listener).HandleLog(newBroadcast(newRoundRequestedLog(t, address, 2, digest, 3, 1)), nil)
+
+	t.Run("stuck", func(t *testing.T) {
+		epoch, stuck := tracker.ExpectedNextEpoch()
+		require.Equal(t, uint32(3), epoch)
+		require.False(t, stuck)
+
+		// A transmission for another config does not count
+		sub.(log.Listener).HandleLog(newBroadcast(newNewTransmissionLog(t, address, 3, ocrtypes.ConfigDigest{9}, 1, 5, 1, 4)), nil)
+		clock.Advance(2 * time.Minute)
+		epoch, stuck = tracker.ExpectedNextEpoch()
+		require.Equal(t, uint32(3), epoch)
+		require.True(t, stuck)
+	})
+
+	t.Run("progressing", func(t *testing.T) {
+		sub.(log.Listener).HandleLog(newBroadcast(newNewTransmissionLog(t, address, 4, digest, 1, 4, 1, 4)), nil)
+		epoch, stuck := tracker.ExpectedNextEpoch()
+		require.Equal(t, uint32(4), epoch)
+		require.False(t, stuck)
+	})
+}
+
+func Test_OCRContractConfigTracker_BlockHeightFor(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, _ := newTestTracker(t, ethClient, new(logmocks.Broadcaster))
+
+	tests := []struct {
+		tag    string
+		number *big.Int
+		height int64
+	}{
+		{"latest", nil, 100},
+		{"pending", big.NewInt(int64(rpc.PendingBlockNumber)), 101},
+		{"safe", big.NewInt(int64(eth.SafeBlockNumber)), 90},
+		{"finalized", big.NewInt(int64(eth.FinalizedBlockNumber)), 80},
+	}
+	for _, test := range tests {
+		ethClient.On("HeaderByNumber", mock.Anything, test.number).Return(&models.Head{Number: test.height}, nil).Once()
+		height, err := tracker.BlockHeightFor(context.Background(), test.tag)
+		require.NoError(t, err)
+		require.Equal(t, uint64(test.height), height, test.tag)
+	}
+	ethClient.AssertExpectations(t)
+
+	_, err := tracker.BlockHeightFor(context.Background(), "earliest")
+	require.Error(t, err)
+}
+
+func Test_OCRContractConfigTracker_CatchUpOnSubscribe_NoConfigChange(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, _ := newTestTracker(t, ethClient, lb, offchainreporting.WithCatchUpOnSubscribe(10))
+
+	// The batch is rejected, and the sequential fallback shows the config was
+	// last set before the catch up range, so no logs are fetched
+	ethClient.On("BatchCallContext", mock.Anything, mock.Anything).Return(errors.New("batch not supported")).Once()
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(&models.Head{Number: 100}, nil).Once()
+	fetched := make(chan struct{})
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 1, 50, [16]byte{1}), nil).Run(func(mock.Arguments) {
+		close(fetched)
+	}).Once()
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	// Close waits for the catch up to finish
+	<-fetched
+	sub.Close()
+	ethClient.AssertExpectations(t)
+	ethClient.AssertNotCalled(t, "FilterLogs", mock.Anything, mock.Anything)
+}
+
+func Test_OCRContractConfigTracker_ConfigFromLogs_TxRelativeLogIndices(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, address := newTestTracker(t, ethClient, new(logmocks.Broadcaster))
+
+	// Two configs set in the same block by consecutive transactions, from a
+	// provider that numbers logs within each transaction and returns them
+	// out of order. Ordering by log index alone would pick the first config.
+	first := newConfigSetLog(t, address, 42, 1)
+	first.TxIndex, first.Index = 0, 1
+	second := newConfigSetLog(t, address, 42, 2)
+	second.TxIndex, second.Index = 1, 0
+	ethClient.On("FilterLogs", mock.Anything, mock.Anything).Return([]types.Log{second, first}, nil)
+
+	cc, err := tracker.ConfigFromLogs(context.Background(), 42)
+	require.NoError(t, err)
+	require.Equal(t, common.BigToAddress(big.NewInt(2)), cc.Signers[0])
+}
+
+func Test_OCRContractConfigTracker_ConfigAgeBlocks(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, address := newTestTracker(t, ethClient, new(logmocks.Broadcaster))
+
+	_, err := tracker.ConfigAgeBlocks(context.Background())
+	require.Equal(t, offchainreporting.ErrNoConfigApplied, err)
+
+	ethClient.On("FilterLogs", mock.Anything, mock.Anything).Return([]types.Log{newConfigSetLog(t, address, 42, 1)}, nil)
+	_, err = tracker.ConfigFromLogs(context.Background(), 42)
+	require.NoError(t, err)
+
+	t.Run("fresh config", func(t *testing.T) {
+		ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(&models.Head{Number: 42}, nil).Once()
+		age, err := tracker.ConfigAgeBlocks(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), age)
+	})
+
+	t.Run("older config", func(t *testing.T) {
+		ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(&models.Head{Number: 142}, nil).Once()
+		age, err := tracker.ConfigAgeBlocks(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(100), age)
+	})
+
+	t.Run("head behind config", func(t *testing.T) {
+		ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(&models.Head{Number: 40}, nil).Once()
+		age, err := tracker.ConfigAgeBlocks(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), age)
+	})
+
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_EarliestConfig(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, address := newTestTracker(t, ethClient, new(logmocks.Broadcaster))
+
+	// The range is scanned in chunks, stopping at the first with a config
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(&models.Head{Number: 12000}, nil).Once()
+	ethClient.On("FilterLogs", mock.Anything, mock.MatchedBy(func(q ethereum.FilterQuery) bool {
+		return q.FromBlock.Int64() == 1000 && q.ToBlock.Int64() == 5999
+	})).Return([]types.Log{}, nil).Once()
+	ethClient.On("FilterLogs", mock.Anything, mock.MatchedBy(func(q ethereum.FilterQuery) bool {
+		return q.FromBlock.Int64() == 6000 && q.ToBlock.Int64() == 10999
+	})).Return([]types.Log{
+		newConfigSetLog(t, address, 7000, 1),
+		newConfigSetLog(t, address, 8000, 2),
+	}, nil).Once()
+
+	cc, blockNumber, err := tracker.EarliestConfig(context.Background(), 1000)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7000), blockNumber)
+	require.Equal(t, common.BigToAddress(big.NewInt(1)), cc.Signers[0])
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_EarliestConfig_NoLogs(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, _ := newTestTracker(t, ethClient, new(logmocks.Broadcaster))
+
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(&models.Head{Number: 100}, nil).Once()
+	ethClient.On("FilterLogs", mock.Anything, mock.MatchedBy(func(q ethereum.FilterQuery) bool {
+		return q.FromBlock.Int64() == 0 && q.ToBlock.Int64() == 100
+	})).Return([]types.Log{}, nil).Once()
+
+	_, _, err := tracker.EarliestConfig(context.Background(), 0)
+	require.Equal(t, offchainreporting.ErrNoConfigLogs, err)
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_RecordAndReplay(t *testing.T) {
+	address := cltest.NewAddress()
+	newTracker := func(client eth.Client) *offchainreporting.OCRContractConfigTracker {
+		contract, err := offchain_aggregator_wrapper.NewOffchainAggregator(address, client)
+		require.NoError(t, err)
+		contractCaller, err := offchainaggregator.NewOffchainAggregatorCaller(address, client)
+		require.NoError(t, err)
+		tracker, err := offchainreporting.NewOCRContractConfigTracker(contract, contractCaller, client, new(logmocks.Broadcaster), 42, *logger.Default)
+		require.NoError(t, err)
+		return tracker
+	}
+	type results struct {
+		height       uint64
+		changedIn    uint64
+		configDigest ocrtypes.ConfigDigest
+		config       ocrtypes.ContractConfig
+	}
+	run := func(tracker *offchainreporting.OCRContractConfigTracker) (r results) {
+		var err error
+		r.height, err = tracker.LatestBlockHeight(context.Background())
+		require.NoError(t, err)
+		r.changedIn, r.configDigest, err = tracker.LatestConfigDetails(context.Background())
+		require.NoError(t, err)
+		r.config, err = tracker.ConfigFromLogs(context.Background(), r.changedIn)
+		require.NoError(t, err)
+		return r
+	}
+
+	ethClient := new(mocks.Client)
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(&models.Head{Number: 50}, nil).Once()
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 1, 42, [16]byte{1}), nil).Once()
+	ethClient.On("FilterLogs", mock.Anything, mock.Anything).Return([]types.Log{newConfigSetLog(t, address, 42, 1)}, nil).Once()
+
+	recorder := ethtestutil.NewRecordingClient(ethClient)
+	recorded := run(newTracker(recorder))
+	ethClient.AssertExpectations(t)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	require.NoError(t, recorder.Save(path))
+
+	replayer, err := ethtestutil.NewReplayClient(path)
+	require.NoError(t, err)
+	tracker := newTracker(replayer)
+	require.Equal(t, recorded, run(tracker))
+
+	_, err = tracker.ConfigFromLogs(context.Background(), 43)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), ethtestutil.ErrNotInCassette.Error())
+}
+
+func Test_OCRContractConfigTracker_BlockHeightCache(t *testing.T) {
+	ethClient := new(mocks.Client)
+	clock := newFakeClock()
+	tracker, _ := newTestTracker(t, ethClient, new(logmocks.Broadcaster),
+		offchainreporting.WithClock(clock),
+		offchainreporting.WithBlockHeightCache(time.Second),
+	)
+
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(&models.Head{Number: 42}, nil).Once()
+	for i := 0; i < 3; i++ {
+		height, err := tracker.LatestBlockHeight(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(42), height)
+		clock.Advance(300 * time.Millisecond)
+	}
+
+	// Errors are not cached
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(nil, errors.New("rpc down")).Once()
+	clock.Advance(100 * time.Millisecond)
+	_, err := tracker.LatestBlockHeight(context.Background())
+	require.EqualError(t, errors.Cause(err), "rpc down")
+
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(&models.Head{Number: 43}, nil).Once()
+	height, err := tracker.LatestBlockHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(43), height)
+
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_BlockHeightCache_ConcurrentMisses(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, _ := newTestTracker(t, ethClient, new(logmocks.Broadcaster), offchainreporting.WithBlockHeightCache(time.Minute))
+
+	// Concurrent misses share a single call, made without holding the cache
+	// lock
+	started, release := make(chan struct{}), make(chan struct{})
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(&models.Head{Number: 42}, nil).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Once()
+	var wg sync.WaitGroup
+	fetch := func() {
+		defer wg.Done()
+		height, err := tracker.LatestBlockHeight(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(42), height)
+	}
+	wg.Add(1)
+	go fetch()
+	<-started
+
+	// A waiter gives up with its own context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := tracker.LatestBlockHeight(ctx)
+	require.Equal(t, context.Canceled, errors.Cause(err))
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go fetch()
+	}
+	close(release)
+	wg.Wait()
+	ethClient.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_ConfigFromLogs_DoesNotRollBack(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, address := newTestTracker(t, ethClient, new(logmocks.Broadcaster))
+
+	ethClient.On("FilterLogs", mock.Anything, mock.Anything).Return([]types.Log{newConfigSetLog(t, address, 10, 2)}, nil).Once()
+	latest, err := tracker.ConfigFromLogs(context.Background(), 10)
+	require.NoError(t, err)
+
+	ethClient.On("FilterLogs", mock.Anything, mock.Anything).Return([]types.Log{newConfigSetLog(t, address, 5, 1)}, nil).Once()
+	old, err := tracker.ConfigFromLogs(context.Background(), 5)
+	require.NoError(t, err)
+	require.NotEqual(t, latest.ConfigDigest, old.ConfigDigest)
+
+	digest, found := tracker.LatestConfigDigest()
+	require.True(t, found)
+	require.Equal(t, latest.ConfigDigest, digest)
+
+	ethClient.AssertExpectations(t)
+}
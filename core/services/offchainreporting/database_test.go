@@ -97,6 +97,52 @@ func Test_DB_ReadWriteState(t *testing.T) {
 	})
 }
 
+func Test_DB_ConfigHistory(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	sqldb, _ := store.DB.DB()
+	key := cltest.MustInsertRandomKey(t, store.DB)
+	spec := cltest.MustInsertOffchainreportingOracleSpec(t, store, key.Address)
+	otherSpec := cltest.MustInsertOffchainreportingOracleSpec(t, store, key.Address)
+	history := offchainreporting.NewConfigHistoryStore(sqldb, spec.ID)
+
+	start := time.Now().Truncate(time.Second)
+	var records []offchainreporting.ConfigRecord
+	for i := 0; i < 3; i++ {
+		record := offchainreporting.ConfigRecord{
+			ContractConfig: ocrtypes.ContractConfig{
+				ConfigDigest:         cltest.MakeConfigDigest(t),
+				Signers:              []common.Address{cltest.NewAddress()},
+				Transmitters:         []common.Address{cltest.NewAddress()},
+				Threshold:            uint8(i + 1),
+				EncodedConfigVersion: 1,
+				Encoded:              []byte{byte(i)},
+			},
+			BlockNumber: uint64(100 + i),
+			AppliedAt:   start.Add(time.Duration(i) * time.Hour),
+		}
+		require.NoError(t, history.WriteConfigRecord(ctx, record))
+		records = append(records, record)
+	}
+	// Records of other jobs are not returned
+	require.NoError(t, offchainreporting.NewConfigHistoryStore(sqldb, otherSpec.ID).WriteConfigRecord(ctx, records[1]))
+
+	read, err := history.ConfigHistoryBetween(ctx, start.Add(30*time.Minute), start.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, read, 2)
+	for i, record := range read {
+		expected := records[i+1]
+		require.True(t, expected.AppliedAt.Equal(record.AppliedAt), "expected %v, got %v", expected.AppliedAt, record.AppliedAt)
+		record.AppliedAt = expected.AppliedAt
+		require.Equal(t, expected, record)
+	}
+
+	read, err = history.ConfigHistoryBetween(ctx, start.Add(3*time.Hour), start.Add(4*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, read, 0)
+}
+
 func Test_DB_ReadWriteConfig(t *testing.T) {
 	store, cleanup := cltest.NewStore(t)
 	defer cleanup()
@@ -22,6 +22,10 @@ import (
 	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
 )
 
+// configAgeUpdateInterval is how often OCR jobs update the
+// seconds-since-config-applied gauge
+const configAgeUpdateInterval = 15 * time.Second
+
 type Delegate struct {
 	db                 *gorm.DB
 	jobORM             job.ORM
@@ -63,11 +67,6 @@ func (d Delegate) ServicesForSpec(jobSpec job.SpecDB) (services []job.Service, e
 		return nil, errors.Wrap(err, "could not instantiate NewOffchainAggregator")
 	}
 
-	contractFilterer, err := offchainaggregator.NewOffchainAggregatorFilterer(concreteSpec.ContractAddress.Address(), d.ethClient)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not instantiate NewOffchainAggregatorFilterer")
-	}
-
 	contractCaller, err := offchainaggregator.NewOffchainAggregatorCaller(concreteSpec.ContractAddress.Address(), d.ethClient)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not instantiate NewOffchainAggregatorCaller")
@@ -75,12 +74,12 @@ func (d Delegate) ServicesForSpec(jobSpec job.SpecDB) (services []job.Service, e
 
 	ocrContract, err := NewOCRContractConfigTracker(
 		contract,
-		contractFilterer,
 		contractCaller,
 		d.ethClient,
 		d.logBroadcaster,
 		jobSpec.ID,
 		*logger.Default,
+		WithConfigAgeUpdates(configAgeUpdateInterval),
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "error calling NewOCRContract")
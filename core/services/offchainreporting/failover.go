@@ -0,0 +1,80 @@
+package offchainreporting
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/internal/gethwrappers/generated/offchain_aggregator_wrapper"
+	"github.com/smartcontractkit/chainlink/core/services/eth"
+	"github.com/smartcontractkit/libocr/gethwrappers/offchainaggregator"
+)
+
+// endpoint is an RPC endpoint the tracker can read from, along with the
+// contract bindings bound to it
+type endpoint struct {
+	client   eth.Client
+	caller   *offchainaggregator.OffchainAggregatorCaller
+	contract *offchain_aggregator_wrapper.OffchainAggregator
+}
+
+// WithFailoverClients adds secondary RPC endpoints. When a read call fails on
+// the active endpoint, the remaining endpoints are tried in order and the
+// first to succeed becomes the active endpoint for subsequent calls. Every
+// read the tracker makes fails over; subscribing to logs does not, as it goes
+// through the log broadcaster.
+func WithFailoverClients(clients ...eth.Client) OCRContractConfigTrackerOption {
+	return func(oc *OCRContractConfigTracker) {
+		oc.failoverClients = append(oc.failoverClients, clients...)
+	}
+}
+
+// initEndpoints binds the contract to each failover client, with the
+// tracker's own client as the primary endpoint
+func (oc *OCRContractConfigTracker) initEndpoints() error {
+	oc.endpoints = []endpoint{{oc.ethClient, oc.contractCaller, oc.contract}}
+	for i, client := range oc.failoverClients {
+		caller, err := offchainaggregator.NewOffchainAggregatorCaller(oc.contract.Address(), client)
+		if err != nil {
+			return errors.Wrapf(err, "could not bind contract caller to failover client %d", i)
+		}
+		contract, err := offchain_aggregator_wrapper.NewOffchainAggregator(oc.contract.Address(), client)
+		if err != nil {
+			return errors.Wrapf(err, "could not bind contract to failover client %d", i)
+		}
+		oc.endpoints = append(oc.endpoints, endpoint{client, caller, contract})
+	}
+	return nil
+}
+
+// withFailover calls f with the active endpoint, then with each of the others
+// in turn until one succeeds. The error from the last endpoint tried is
+// returned if all fail. Errors that are not the endpoint's fault, a done
+// context or a reverted call, are returned straight away without failing
+// over.
+func (oc *OCRContractConfigTracker) withFailover(ctx context.Context, f func(e endpoint) error) (err error) {
+	active := int(atomic.LoadUint32(&oc.activeEndpoint))
+	for i := 0; i < len(oc.endpoints); i++ {
+		index := (active + i) % len(oc.endpoints)
+		if err = f(oc.endpoints[index]); err == nil {
+			if index != active && atomic.CompareAndSwapUint32(&oc.activeEndpoint, uint32(active), uint32(index)) {
+				oc.logger.Warnw("OCRContract: RPC endpoint failed, switched to failover endpoint", "endpoint", index)
+				oc.metrics.SetGauge(MetricActiveEndpoint, float64(index), map[string]string{"contract_address": oc.contract.Address().Hex()})
+			}
+			return nil
+		}
+		if ctx.Err() != nil || isExecutionReverted(err) {
+			return err
+		}
+		if len(oc.endpoints) > 1 {
+			oc.logger.Debugw("OCRContract: RPC call failed on endpoint", "endpoint", index, "err", err)
+		}
+	}
+	return err
+}
+
+// ActiveEndpoint returns the index of the endpoint currently used for read
+// calls, 0 being the tracker's own client and 1 onwards the failover clients
+func (oc *OCRContractConfigTracker) ActiveEndpoint() int {
+	return int(atomic.LoadUint32(&oc.activeEndpoint))
+}
@@ -0,0 +1,103 @@
+package offchainreporting_test
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/gethwrappers/generated/offchain_aggregator_wrapper"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OCRContractConfigTracker_FailoverClients(t *testing.T) {
+	primary := new(mocks.Client)
+	secondary := new(mocks.Client)
+	sink := &fakeMetricsSink{}
+	tracker, address := newTestTracker(t, primary, new(logmocks.Broadcaster),
+		offchainreporting.WithFailoverClients(secondary),
+		offchainreporting.WithMetricsSink(sink),
+	)
+	require.Equal(t, 0, tracker.ActiveEndpoint())
+
+	primary.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(nil, errors.New("primary down")).Once()
+	secondary.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(&models.Head{Number: 42}, nil).Once()
+	height, err := tracker.LatestBlockHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), height)
+	require.Equal(t, 1, tracker.ActiveEndpoint())
+	require.Equal(t, []fakeMetric{
+		{offchainreporting.MetricActiveEndpoint, 1, map[string]string{"contract_address": address.Hex()}},
+	}, sink.metrics)
+
+	// Subsequent calls go straight to the active endpoint
+	secondary.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 1, 42, [16]byte{1}), nil).Once()
+	changedInBlock, _, err := tracker.LatestConfigDetails(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), changedInBlock)
+
+	// Errors from the last endpoint tried are returned if all fail
+	secondary.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(nil, errors.New("secondary down")).Once()
+	primary.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(nil, errors.New("primary still down")).Once()
+	_, err = tracker.LatestBlockHeight(context.Background())
+	require.EqualError(t, errors.Cause(err), "primary still down")
+	require.Equal(t, 1, tracker.ActiveEndpoint())
+
+	// A revert is the contract's answer, not an endpoint fault
+	secondary.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("execution reverted")).Once()
+	_, _, err = tracker.LatestConfigDetails(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "execution reverted")
+	require.Equal(t, 1, tracker.ActiveEndpoint())
+
+	// As is a done context
+	ctx, cancel := context.WithCancel(context.Background())
+	secondary.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(nil, context.Canceled).Run(func(mock.Arguments) {
+		cancel()
+	}).Once()
+	_, err = tracker.LatestBlockHeight(ctx)
+	require.Equal(t, context.Canceled, errors.Cause(err))
+	require.Equal(t, 1, tracker.ActiveEndpoint())
+
+	primary.AssertExpectations(t)
+	secondary.AssertExpectations(t)
+}
+
+func Test_OCRContractConfigTracker_FailoverClients_AllReads(t *testing.T) {
+	primary := new(mocks.Client)
+	secondary := new(mocks.Client)
+	tracker, _ := newTestTracker(t, primary, new(logmocks.Broadcaster), offchainreporting.WithFailoverClients(secondary))
+
+	timestamp := time.Unix(1600000000, 0).UTC()
+	primary.On("HeaderByNumber", mock.Anything, big.NewInt(7)).Return(nil, errors.New("primary down")).Once()
+	secondary.On("HeaderByNumber", mock.Anything, big.NewInt(7)).Return(&models.Head{Number: 7, Timestamp: timestamp}, nil).Once()
+	ts, err := tracker.BlockTimestamp(context.Background(), 7)
+	require.NoError(t, err)
+	require.Equal(t, timestamp, ts)
+	require.Equal(t, 1, tracker.ActiveEndpoint())
+
+	// Contract reads go to the active endpoint, and fail back over
+	owner := cltest.NewAddress()
+	contractABI, err := abi.JSON(strings.NewReader(offchain_aggregator_wrapper.OffchainAggregatorABI))
+	require.NoError(t, err)
+	b, err := contractABI.Methods["owner"].Outputs.Pack(owner)
+	require.NoError(t, err)
+	secondary.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("secondary down")).Once()
+	primary.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(b, nil).Once()
+	got, err := tracker.Owner(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, owner, got)
+	require.Equal(t, 0, tracker.ActiveEndpoint())
+
+	primary.AssertExpectations(t)
+	secondary.AssertExpectations(t)
+}
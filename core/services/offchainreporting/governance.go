@@ -0,0 +1,73 @@
+package offchainreporting
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/chainlink/core/utils"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+)
+
+var (
+	proposedConfigDigestSelector = utils.MustHash("proposedConfigDigest()").Bytes()[:4]
+	proposedConfigDigestOutputs  = abi.Arguments{{Type: mustNewType("bytes16")}}
+)
+
+// Owner returns the owner of the contract
+func (oc *OCRContractConfigTracker) Owner(ctx context.Context) (owner gethCommon.Address, err error) {
+	opts := bind.CallOpts{Context: ctx, Pending: false}
+	err = oc.call(ctx, func() error {
+		return oc.withFailover(ctx, func(e endpoint) (err2 error) {
+			owner, err2 = e.contract.Owner(&opts)
+			return err2
+		})
+	})
+	if err != nil {
+		return owner, oc.wrapErr(err, "error getting owner")
+	}
+	return owner, nil
+}
+
+// ProposedConfigDigest returns the digest of the config proposed to the
+// contract but not yet set, for contracts with a two-step config proposal
+// flow. found is false if no config is proposed, or if the contract has no
+// proposal flow, as is the case for the OffchainAggregator.
+func (oc *OCRContractConfigTracker) ProposedConfigDigest(ctx context.Context) (digest ocrtypes.ConfigDigest, found bool, err error) {
+	address := oc.contract.Address()
+	var out []byte
+	err = oc.call(ctx, func() error {
+		return oc.withFailover(ctx, func(e endpoint) (err2 error) {
+			out, err2 = e.client.CallContract(ctx, ethereum.CallMsg{To: &address, Data: proposedConfigDigestSelector}, nil)
+			return err2
+		})
+	})
+	if err != nil && isExecutionReverted(err) {
+		return digest, false, nil
+	} else if err != nil {
+		return digest, false, oc.wrapErr(err, "could not call proposedConfigDigest")
+	}
+	if len(out) == 0 {
+		return digest, false, nil
+	}
+	values, err := proposedConfigDigestOutputs.Unpack(out)
+	if err != nil {
+		return digest, false, oc.wrapErr(err, "could not decode proposedConfigDigest")
+	}
+	raw, ok := values[0].([16]byte)
+	if !ok {
+		return digest, false, oc.errorf("unexpected proposedConfigDigest %v of type %T", values[0], values[0])
+	}
+	digest = ocrtypes.ConfigDigest(raw)
+	return digest, digest != ocrtypes.ConfigDigest{}, nil
+}
+
+// isExecutionReverted reports whether the error from an eth_call means the
+// call reverted, as calls to functions a contract does not implement do
+func isExecutionReverted(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "execution reverted") || strings.Contains(msg, "vm execution error")
+}
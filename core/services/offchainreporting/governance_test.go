@@ -0,0 +1,88 @@
+package offchainreporting_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/gethwrappers/generated/offchain_aggregator_wrapper"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OCRContractConfigTracker_Owner(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, address := newTestTracker(t, ethClient, new(logmocks.Broadcaster))
+
+	owner := cltest.NewAddress()
+	contractABI, err := abi.JSON(strings.NewReader(offchain_aggregator_wrapper.OffchainAggregatorABI))
+	require.NoError(t, err)
+	b, err := contractABI.Methods["owner"].Outputs.Pack(owner)
+	require.NoError(t, err)
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(b, nil).Once()
+
+	got, err := tracker.Owner(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, owner, got)
+
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("boom")).Once()
+	_, err = tracker.Owner(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), address.Hex())
+}
+
+func Test_OCRContractConfigTracker_ProposedConfigDigest(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, _ := newTestTracker(t, ethClient, new(logmocks.Broadcaster))
+
+	isProposedConfigDigestCall := mock.MatchedBy(func(msg ethereum.CallMsg) bool {
+		return bytes.Equal(msg.Data, crypto.Keccak256([]byte("proposedConfigDigest()"))[:4])
+	})
+	bytes16Type, err := abi.NewType("bytes16", "", nil)
+	require.NoError(t, err)
+	encode := func(digest [16]byte) []byte {
+		b, err := abi.Arguments{{Type: bytes16Type}}.Pack(digest)
+		require.NoError(t, err)
+		return b
+	}
+
+	t.Run("proposed", func(t *testing.T) {
+		ethClient.On("CallContract", mock.Anything, isProposedConfigDigestCall, (*big.Int)(nil)).Return(encode([16]byte{7}), nil).Once()
+		digest, found, err := tracker.ProposedConfigDigest(context.Background())
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, ocrtypes.ConfigDigest{7}, digest)
+	})
+
+	t.Run("nothing proposed", func(t *testing.T) {
+		ethClient.On("CallContract", mock.Anything, isProposedConfigDigestCall, (*big.Int)(nil)).Return(encode([16]byte{}), nil).Once()
+		_, found, err := tracker.ProposedConfigDigest(context.Background())
+		require.NoError(t, err)
+		require.False(t, found)
+	})
+
+	t.Run("no proposal flow", func(t *testing.T) {
+		ethClient.On("CallContract", mock.Anything, isProposedConfigDigestCall, (*big.Int)(nil)).Return(nil, errors.New("execution reverted")).Once()
+		_, found, err := tracker.ProposedConfigDigest(context.Background())
+		require.NoError(t, err)
+		require.False(t, found)
+	})
+
+	t.Run("rpc error", func(t *testing.T) {
+		ethClient.On("CallContract", mock.Anything, isProposedConfigDigestCall, (*big.Int)(nil)).Return(nil, errors.New("connection refused")).Once()
+		_, _, err := tracker.ProposedConfigDigest(context.Background())
+		require.Error(t, err)
+	})
+
+	ethClient.AssertExpectations(t)
+}
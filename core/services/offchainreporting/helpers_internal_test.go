@@ -0,0 +1,30 @@
+package offchainreporting
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/smartcontractkit/chainlink/core/services/log"
+)
+
+var (
+	PromOCRTrackerUnrecognizedLogs          = defaultPromMetrics.unrecognizedLogs
+	PromOCRTrackerConfigsApplied            = defaultPromMetrics.configsApplied
+	PromOCRTrackerSecondsSinceConfigApplied = defaultPromMetrics.secondsSinceConfigApplied
+)
+
+func (oc *OCRContractConfigTracker) ExportedConfigStillCanonical(ctx context.Context) (bool, error) {
+	return oc.configStillCanonical(ctx)
+}
+
+func (sub *OCRContractConfigSubscription) ExportedHandleConfigSet(raw types.Log) bool {
+	return sub.handleConfigSet(raw)
+}
+
+func (sub *OCRContractConfigSubscription) ExportedHandleLogs(broadcasts []log.Broadcast) {
+	sub.handleLogs(broadcasts)
+}
+
+func (sub *OCRContractConfigSubscription) ExportedHasWorker() bool {
+	return sub.processLogsWorker != nil
+}
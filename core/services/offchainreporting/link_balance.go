@@ -0,0 +1,36 @@
+package offchainreporting
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/internal/gethwrappers/generated/link_token_interface"
+)
+
+// LinkBalance returns the contract's balance of the LINK token at
+// linkTokenAddress, out of which it pays the oracles, and reports it as the
+// MetricLinkBalance gauge
+func (oc *OCRContractConfigTracker) LinkBalance(ctx context.Context, linkTokenAddress gethCommon.Address) (*big.Int, error) {
+	address := oc.contract.Address()
+	opts := bind.CallOpts{Context: ctx, Pending: false}
+	var balance *big.Int
+	err := oc.call(ctx, func() error {
+		return oc.withFailover(ctx, func(e endpoint) error {
+			linkToken, err2 := link_token_interface.NewLinkTokenCaller(linkTokenAddress, e.client)
+			if err2 != nil {
+				return errors.Wrap(err2, "could not create LINK token caller")
+			}
+			balance, err2 = linkToken.BalanceOf(&opts, address)
+			return err2
+		})
+	})
+	if err != nil {
+		return nil, oc.wrapErr(err, "error getting LINK balance")
+	}
+	value, _ := new(big.Float).SetInt(balance).Float64()
+	oc.metrics.SetGauge(MetricLinkBalance, value, map[string]string{"contract_address": address.Hex()})
+	return balance, nil
+}
@@ -0,0 +1,48 @@
+package offchainreporting_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OCRContractConfigTracker_LinkBalance(t *testing.T) {
+	ethClient := new(mocks.Client)
+	sink := &fakeMetricsSink{}
+	tracker, address := newTestTracker(t, ethClient, new(logmocks.Broadcaster), offchainreporting.WithMetricsSink(sink))
+	linkTokenAddress := cltest.NewAddress()
+
+	uint256Type, err := abi.NewType("uint256", "", nil)
+	require.NoError(t, err)
+	b, err := abi.Arguments{{Type: uint256Type}}.Pack(big.NewInt(5e18))
+	require.NoError(t, err)
+	isBalanceOfCall := mock.MatchedBy(func(msg ethereum.CallMsg) bool {
+		return *msg.To == linkTokenAddress && bytes.Equal(msg.Data[:4], crypto.Keccak256([]byte("balanceOf(address)"))[:4]) && bytes.Equal(msg.Data[4:], address.Hash().Bytes())
+	})
+	ethClient.On("CallContract", mock.Anything, isBalanceOfCall, mock.Anything).Return(b, nil).Once()
+
+	balance, err := tracker.LinkBalance(context.Background(), linkTokenAddress)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(5e18), balance)
+	require.Equal(t, []fakeMetric{
+		{offchainreporting.MetricLinkBalance, 5e18, map[string]string{"contract_address": address.Hex()}},
+	}, sink.metrics)
+
+	ethClient.On("CallContract", mock.Anything, isBalanceOfCall, mock.Anything).Return(nil, errors.New("boom")).Once()
+	_, err = tracker.LinkBalance(context.Background(), linkTokenAddress)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), address.Hex())
+	ethClient.AssertExpectations(t)
+}
@@ -0,0 +1,108 @@
+package offchainreporting
+
+import (
+	gethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/libocr/gethwrappers/offchainaggregator"
+	"github.com/smartcontractkit/libocr/offchainreporting/confighelper"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+)
+
+// offchainAggregatorLogParser only uses the ABI to unpack logs, so it is not
+// tied to any particular contract address or backend
+var offchainAggregatorLogParser = mustNewOffchainAggregatorFilterer()
+
+func mustNewOffchainAggregatorFilterer() *offchainaggregator.OffchainAggregatorFilterer {
+	filterer, err := offchainaggregator.NewOffchainAggregatorFilterer(gethCommon.Address{}, nil)
+	if err != nil {
+		panic("could not create OffchainAggregator filterer: " + err.Error())
+	}
+	return filterer
+}
+
+// ParseOCRConfigSet parses a raw OffchainAggregator ConfigSet log into the
+// contract config it sets. It does not check the address of the log.
+func ParseOCRConfigSet(raw types.Log) (ocrtypes.ContractConfig, error) {
+	configSet, err := parseConfigSetEvent(raw)
+	if err != nil {
+		return ocrtypes.ContractConfig{}, err
+	}
+	return confighelper.ContractConfigFromConfigSetEvent(*configSet), nil
+}
+
+// ParseOCRRoundRequested parses a raw OffchainAggregator RoundRequested log.
+// It does not check the address of the log.
+func ParseOCRRoundRequested(raw types.Log) (*offchainaggregator.OffchainAggregatorRoundRequested, error) {
+	if err := validateEventTopic(raw, "RoundRequested"); err != nil {
+		return nil, err
+	}
+	if err := validateTopicCount(raw, "RoundRequested"); err != nil {
+		return nil, err
+	}
+	rr, err := offchainAggregatorLogParser.ParseRoundRequested(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse RoundRequested in block %d", raw.BlockNumber)
+	}
+	if rr.Requester == (gethCommon.Address{}) {
+		// Guard against a generated filterer that does not treat the requester
+		// as indexed
+		topic, err := models.LogTopicAt(raw, 1)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read requester of RoundRequested in block %d", raw.BlockNumber)
+		}
+		rr.Requester = gethCommon.BytesToAddress(topic.Bytes())
+	}
+	rr.Raw = raw
+	return rr, nil
+}
+
+// ParseOCRNewTransmission parses a raw OffchainAggregator NewTransmission
+// log. It does not check the address of the log.
+func ParseOCRNewTransmission(raw types.Log) (*offchainaggregator.OffchainAggregatorNewTransmission, error) {
+	if err := validateEventTopic(raw, "NewTransmission"); err != nil {
+		return nil, err
+	}
+	if err := validateTopicCount(raw, "NewTransmission"); err != nil {
+		return nil, err
+	}
+	nt, err := offchainAggregatorLogParser.ParseNewTransmission(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse NewTransmission in block %d", raw.BlockNumber)
+	}
+	nt.Raw = raw
+	return nt, nil
+}
+
+func parseConfigSetEvent(raw types.Log) (*offchainaggregator.OffchainAggregatorConfigSet, error) {
+	if err := validateEventTopic(raw, "ConfigSet"); err != nil {
+		return nil, err
+	}
+	if err := validateTopicCount(raw, "ConfigSet"); err != nil {
+		return nil, err
+	}
+	configSet, err := offchainAggregatorLogParser.ParseConfigSet(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse ConfigSet in block %d", raw.BlockNumber)
+	}
+	configSet.Raw = raw
+	return configSet, nil
+}
+
+// validateEventTopic checks that the log's first topic is the signature of
+// the named event. The unpacker does not check this itself, so a log routed
+// to the wrong parser would otherwise be silently misparsed.
+func validateEventTopic(raw types.Log, eventName string) error {
+	event, exists := offchainAggregatorABI.Events[eventName]
+	if !exists {
+		return errors.Errorf("unknown OffchainAggregator event %s", eventName)
+	}
+	if len(raw.Topics) == 0 {
+		return errors.Errorf("%s log in tx 0x%x has no topics", eventName, raw.TxHash)
+	}
+	if raw.Topics[0] != event.ID {
+		return errors.Errorf("%s log in tx 0x%x has topic 0x%x, expected 0x%x", eventName, raw.TxHash, raw.Topics[0], event.ID)
+	}
+	return nil
+}
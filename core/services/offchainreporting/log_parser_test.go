@@ -0,0 +1,66 @@
+package offchainreporting_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseOCRConfigSet(t *testing.T) {
+	address := cltest.NewAddress()
+	raw := newConfigSetLog(t, address, 42, 3)
+
+	cc, err := offchainreporting.ParseOCRConfigSet(raw)
+	require.NoError(t, err)
+	require.Equal(t, []common.Address{common.BigToAddress(big.NewInt(3))}, cc.Signers)
+	require.Equal(t, uint8(1), cc.Threshold)
+	require.Equal(t, uint64(1), cc.EncodedConfigVersion)
+	require.Equal(t, []byte{1, 2, 3}, cc.Encoded)
+
+	raw.Topics = append(raw.Topics, cltest.NewHash())
+	_, err = offchainreporting.ParseOCRConfigSet(raw)
+	require.Error(t, err)
+}
+
+func Test_ParseOCRRoundRequested(t *testing.T) {
+	address := cltest.NewAddress()
+	raw := newRoundRequestedLog(t, address, 42, ocrtypes.ConfigDigest{1}, 7, 2)
+
+	rr, err := offchainreporting.ParseOCRRoundRequested(raw)
+	require.NoError(t, err)
+	require.Equal(t, common.BytesToAddress(raw.Topics[1].Bytes()), rr.Requester)
+	require.Equal(t, [16]byte{1}, rr.ConfigDigest)
+	require.Equal(t, uint32(7), rr.Epoch)
+	require.Equal(t, uint8(2), rr.Round)
+	require.Equal(t, raw, rr.Raw)
+
+	raw.Data = raw.Data[:len(raw.Data)-1]
+	_, err = offchainreporting.ParseOCRRoundRequested(raw)
+	require.Error(t, err)
+}
+
+func Test_ParseOCRRoundRequested_RequesterFromTopic(t *testing.T) {
+	requester := cltest.NewAddress()
+	raw := newRoundRequestedLog(t, cltest.NewAddress(), 42, ocrtypes.ConfigDigest{1}, 7, 2)
+	raw.Topics[1] = requester.Hash()
+
+	// The data holds only the non-indexed fields, so the requester can only
+	// come from the topic
+	rr, err := offchainreporting.ParseOCRRoundRequested(raw)
+	require.NoError(t, err)
+	require.Equal(t, requester, rr.Requester)
+
+	raw.Topics[1] = common.Hash{}
+	rr, err = offchainreporting.ParseOCRRoundRequested(raw)
+	require.NoError(t, err)
+	require.Equal(t, common.Address{}, rr.Requester)
+
+	raw.Topics = raw.Topics[:1]
+	_, err = offchainreporting.ParseOCRRoundRequested(raw)
+	require.Error(t, err)
+}
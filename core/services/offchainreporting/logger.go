@@ -1,8 +1,13 @@
 package offchainreporting
 
 import (
+	"sync"
+	"time"
+
 	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/utils"
 	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+	"go.uber.org/zap/zapcore"
 )
 
 var _ ocrtypes.Logger = &ocrLogger{}
@@ -56,3 +61,49 @@ func toKeysAndValues(fields ocrtypes.LogFields) []interface{} {
 	}
 	return out
 }
+
+// rateLimitedLogger logs a message at a fixed level at most once per interval,
+// reporting how many messages were suppressed in between. An interval of 0
+// logs every message.
+type rateLimitedLogger struct {
+	logw     func(msg string, keysAndValues ...interface{})
+	interval time.Duration
+	clock    utils.Nower
+
+	mu         sync.Mutex
+	last       time.Time
+	suppressed int
+}
+
+func newRateLimitedLogger(l logger.Logger, level zapcore.Level, interval time.Duration, clock utils.Nower) *rateLimitedLogger {
+	var logw func(msg string, keysAndValues ...interface{})
+	switch level {
+	case zapcore.DebugLevel:
+		logw = l.Debugw
+	case zapcore.InfoLevel:
+		logw = l.Infow
+	case zapcore.WarnLevel:
+		logw = l.Warnw
+	default:
+		logw = l.Errorw
+	}
+	return &rateLimitedLogger{logw: logw, interval: interval, clock: clock}
+}
+
+func (rl *rateLimitedLogger) Logw(msg string, keysAndValues ...interface{}) {
+	rl.mu.Lock()
+	now := rl.clock.Now()
+	if !rl.last.IsZero() && now.Sub(rl.last) < rl.interval {
+		rl.suppressed++
+		rl.mu.Unlock()
+		return
+	}
+	if rl.suppressed > 0 {
+		keysAndValues = append(keysAndValues, "suppressed", rl.suppressed)
+	}
+	rl.last = now
+	rl.suppressed = 0
+	rl.mu.Unlock()
+
+	rl.logw(msg, keysAndValues...)
+}
@@ -0,0 +1,53 @@
+package offchainreporting
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// Decimals returns the number of decimals in the answers reported by the
+// contract. It is immutable, so the contract is only called once.
+func (oc *OCRContractConfigTracker) Decimals(ctx context.Context) (uint8, error) {
+	oc.metadataMu.Lock()
+	defer oc.metadataMu.Unlock()
+	if oc.decimals != nil {
+		return *oc.decimals, nil
+	}
+	opts := bind.CallOpts{Context: ctx, Pending: false}
+	var decimals uint8
+	err := oc.call(ctx, func() error {
+		return oc.withFailover(ctx, func(e endpoint) (err2 error) {
+			decimals, err2 = e.contract.Decimals(&opts)
+			return err2
+		})
+	})
+	if err != nil {
+		return 0, oc.wrapErr(err, "error getting decimals")
+	}
+	oc.decimals = &decimals
+	return decimals, nil
+}
+
+// Description returns the description of the feed reported by the contract.
+// It is immutable, so the contract is only called once.
+func (oc *OCRContractConfigTracker) Description(ctx context.Context) (string, error) {
+	oc.metadataMu.Lock()
+	defer oc.metadataMu.Unlock()
+	if oc.description != nil {
+		return *oc.description, nil
+	}
+	opts := bind.CallOpts{Context: ctx, Pending: false}
+	var description string
+	err := oc.call(ctx, func() error {
+		return oc.withFailover(ctx, func(e endpoint) (err2 error) {
+			description, err2 = e.contract.Description(&opts)
+			return err2
+		})
+	})
+	if err != nil {
+		return "", oc.wrapErr(err, "error getting description")
+	}
+	oc.description = &description
+	return description, nil
+}
@@ -0,0 +1,55 @@
+package offchainreporting_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/internal/gethwrappers/generated/offchain_aggregator_wrapper"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OCRContractConfigTracker_DecimalsAndDescription(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, address := newTestTracker(t, ethClient, new(logmocks.Broadcaster))
+
+	contractABI, err := abi.JSON(strings.NewReader(offchain_aggregator_wrapper.OffchainAggregatorABI))
+	require.NoError(t, err)
+	isCall := func(signature string) interface{} {
+		return mock.MatchedBy(func(msg ethereum.CallMsg) bool {
+			return bytes.Equal(msg.Data, crypto.Keccak256([]byte(signature))[:4])
+		})
+	}
+
+	ethClient.On("CallContract", mock.Anything, isCall("decimals()"), mock.Anything).Return(nil, errors.New("boom")).Once()
+	_, err = tracker.Decimals(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), address.Hex())
+
+	b, err := contractABI.Methods["decimals"].Outputs.Pack(uint8(8))
+	require.NoError(t, err)
+	ethClient.On("CallContract", mock.Anything, isCall("decimals()"), mock.Anything).Return(b, nil).Once()
+	b, err = contractABI.Methods["description"].Outputs.Pack("ETH / USD")
+	require.NoError(t, err)
+	ethClient.On("CallContract", mock.Anything, isCall("description()"), mock.Anything).Return(b, nil).Once()
+
+	// The second calls are served from the cache
+	for i := 0; i < 2; i++ {
+		decimals, err := tracker.Decimals(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint8(8), decimals)
+
+		description, err := tracker.Description(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "ETH / USD", description)
+	}
+	ethClient.AssertExpectations(t)
+}
@@ -0,0 +1,141 @@
+package offchainreporting
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Names of the metrics emitted by the OCRContractConfigTracker
+const (
+	MetricUnrecognizedLogs          = "ocr_contract_tracker_unrecognized_logs"
+	MetricConfigsApplied            = "ocr_contract_tracker_configs_applied"
+	MetricSecondsSinceConfigApplied = "ocr_contract_tracker_seconds_since_config_applied"
+	MetricActiveEndpoint            = "ocr_contract_tracker_active_endpoint"
+	MetricBlockGaps                 = "ocr_contract_tracker_block_gaps"
+	MetricConfigDrift               = "ocr_contract_tracker_config_drift"
+	MetricOversizedLogs             = "ocr_contract_tracker_oversized_logs"
+	MetricPausedHighWater           = "ocr_contract_tracker_paused_high_water"
+	MetricLinkBalance               = "ocr_contract_tracker_link_balance"
+)
+
+type (
+	// MetricsSink receives the metrics emitted by the OCRContractConfigTracker.
+	// Implementations must be safe for concurrent use and must not block.
+	MetricsSink interface {
+		IncCounter(name string, labels map[string]string)
+		SetGauge(name string, value float64, labels map[string]string)
+	}
+
+	promMetricsSink struct {
+		metrics *promMetrics
+	}
+
+	statsdMetricsSink struct {
+		w io.Writer
+	}
+
+	// multiMetricsSink sends every metric to each of its sinks
+	multiMetricsSink []MetricsSink
+)
+
+var (
+	_ MetricsSink = promMetricsSink{}
+	_ MetricsSink = &statsdMetricsSink{}
+	_ MetricsSink = multiMetricsSink{}
+)
+
+// newMetricsSink returns the sink given WithMetricsSink, the Prometheus
+// metrics of the registry given WithPrometheusRegistry, or both if both
+// options were given. It defaults to the default Prometheus metrics.
+func (oc *OCRContractConfigTracker) newMetricsSink() MetricsSink {
+	var prom MetricsSink = promMetricsSink{defaultPromMetrics}
+	if oc.promRegistry != nil {
+		prom = promMetricsSink{newPromMetrics(oc.promRegistry)}
+	}
+	switch {
+	case oc.metricsSink == nil:
+		return prom
+	case oc.promRegistry == nil:
+		return oc.metricsSink
+	default:
+		return multiMetricsSink{prom, oc.metricsSink}
+	}
+}
+
+// IncCounter complies with MetricsSink interface
+func (s promMetricsSink) IncCounter(name string, labels map[string]string) {
+	switch name {
+	case MetricUnrecognizedLogs:
+		s.metrics.unrecognizedLogs.With(prometheus.Labels(labels)).Inc()
+	case MetricConfigsApplied:
+		s.metrics.configsApplied.With(prometheus.Labels(labels)).Inc()
+	case MetricBlockGaps:
+		s.metrics.blockGaps.With(prometheus.Labels(labels)).Inc()
+	case MetricOversizedLogs:
+		s.metrics.oversizedLogs.With(prometheus.Labels(labels)).Inc()
+	case MetricPausedHighWater:
+		s.metrics.pausedHighWater.With(prometheus.Labels(labels)).Inc()
+	}
+}
+
+// SetGauge complies with MetricsSink interface
+func (s promMetricsSink) SetGauge(name string, value float64, labels map[string]string) {
+	switch name {
+	case MetricSecondsSinceConfigApplied:
+		s.metrics.secondsSinceConfigApplied.With(prometheus.Labels(labels)).Set(value)
+	case MetricActiveEndpoint:
+		s.metrics.activeEndpoint.With(prometheus.Labels(labels)).Set(value)
+	case MetricConfigDrift:
+		s.metrics.configDrift.With(prometheus.Labels(labels)).Set(value)
+	case MetricLinkBalance:
+		s.metrics.linkBalance.With(prometheus.Labels(labels)).Set(value)
+	}
+}
+
+// NewStatsdMetricsSink returns a MetricsSink that writes metrics in the
+// statsd line protocol, with labels as DogStatsD tags, to w. w is typically
+// a UDP connection to the statsd agent; write errors are ignored since
+// statsd is best-effort.
+func NewStatsdMetricsSink(w io.Writer) MetricsSink {
+	return &statsdMetricsSink{w}
+}
+
+// IncCounter complies with MetricsSink interface
+func (s *statsdMetricsSink) IncCounter(name string, labels map[string]string) {
+	s.write(fmt.Sprintf("%s:1|c", name), labels)
+}
+
+// SetGauge complies with MetricsSink interface
+func (s *statsdMetricsSink) SetGauge(name string, value float64, labels map[string]string) {
+	s.write(fmt.Sprintf("%s:%g|g", name, value), labels)
+}
+
+func (s *statsdMetricsSink) write(metric string, labels map[string]string) {
+	if len(labels) > 0 {
+		tags := make([]string, 0, len(labels))
+		for k, v := range labels {
+			tags = append(tags, k+":"+v)
+		}
+		sort.Strings(tags)
+		metric += "|#" + strings.Join(tags, ",")
+	}
+	_, _ = s.w.Write([]byte(metric + "\n"))
+}
+
+// IncCounter complies with MetricsSink interface
+func (s multiMetricsSink) IncCounter(name string, labels map[string]string) {
+	for _, sink := range s {
+		sink.IncCounter(name, labels)
+	}
+}
+
+// SetGauge complies with MetricsSink interface
+func (s multiMetricsSink) SetGauge(name string, value float64, labels map[string]string) {
+	for _, sink := range s {
+		sink.SetGauge(name, value, labels)
+	}
+}
@@ -0,0 +1,155 @@
+package offchainreporting_test
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/log"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMetric struct {
+	name   string
+	value  float64
+	labels map[string]string
+}
+
+type fakeMetricsSink struct {
+	mu      sync.Mutex
+	metrics []fakeMetric
+}
+
+func (s *fakeMetricsSink) IncCounter(name string, labels map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metrics = append(s.metrics, fakeMetric{name, 1, labels})
+}
+
+func (s *fakeMetricsSink) SetGauge(name string, value float64, labels map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metrics = append(s.metrics, fakeMetric{name, value, labels})
+}
+
+func Test_OCRContractConfigTracker_MetricsSink(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	sink := &fakeMetricsSink{}
+	clock := newFakeClock()
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly(), offchainreporting.WithClock(clock), offchainreporting.WithMetricsSink(sink), offchainreporting.WithConfigAgeUpdates(time.Minute))
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	topic := cltest.NewHash()
+	sub.(log.Listener).HandleLog(newBroadcast(types.Log{Address: address, Topics: []common.Hash{topic}}), nil)
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 1, 1)), nil)
+	clock.awaitAfter(t, time.Minute)
+	clock.Advance(time.Minute)
+	clock.awaitAfter(t, time.Minute)
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	labels := map[string]string{"contract_address": address.Hex()}
+	require.Equal(t, []fakeMetric{
+		{offchainreporting.MetricUnrecognizedLogs, 1, map[string]string{"contract_address": address.Hex(), "topic": topic.Hex()}},
+		{offchainreporting.MetricConfigsApplied, 1, labels},
+		{offchainreporting.MetricSecondsSinceConfigApplied, 0, labels},
+		{offchainreporting.MetricSecondsSinceConfigApplied, 60, labels},
+	}, sink.metrics)
+}
+
+// sumCounters returns the sum of the counters in the named metric family
+// gathered from the registry
+func sumCounters(t *testing.T, registry *prometheus.Registry, name string) (sum float64) {
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			sum += metric.GetCounter().GetValue()
+		}
+	}
+	return sum
+}
+
+func Test_OCRContractConfigTracker_PrometheusRegistry(t *testing.T) {
+	lb := new(logmocks.Broadcaster)
+	lb.On("Register", mock.Anything, mock.Anything).Return(true)
+	lb.On("Unregister", mock.Anything, mock.Anything).Return()
+
+	registry1, registry2 := prometheus.NewRegistry(), prometheus.NewRegistry()
+	tracker1, address1 := newTestTracker(t, new(mocks.Client), lb, offchainreporting.WithReadOnly(), offchainreporting.WithPrometheusRegistry(registry1))
+	tracker2, address2 := newTestTracker(t, new(mocks.Client), lb, offchainreporting.WithReadOnly(), offchainreporting.WithPrometheusRegistry(registry2))
+	// Sharing a registry reuses its collectors rather than panicking
+	tracker3, address3 := newTestTracker(t, new(mocks.Client), lb, offchainreporting.WithReadOnly(), offchainreporting.WithPrometheusRegistry(registry2))
+
+	for _, test := range []struct {
+		tracker *offchainreporting.OCRContractConfigTracker
+		address common.Address
+		logs    int
+	}{
+		{tracker1, address1, 1},
+		{tracker2, address2, 2},
+		{tracker3, address3, 3},
+	} {
+		sub, err := test.tracker.SubscribeToNewConfigs(context.Background())
+		require.NoError(t, err)
+		defer sub.Close()
+		for i := 0; i < test.logs; i++ {
+			sub.(log.Listener).HandleLog(newBroadcast(types.Log{Address: test.address, Topics: []common.Hash{cltest.NewHash()}}), nil)
+		}
+	}
+
+	require.Equal(t, float64(1), sumCounters(t, registry1, offchainreporting.MetricUnrecognizedLogs))
+	require.Equal(t, float64(5), sumCounters(t, registry2, offchainreporting.MetricUnrecognizedLogs))
+}
+
+func Test_OCRContractConfigTracker_PrometheusRegistryAndMetricsSink(t *testing.T) {
+	lb := new(logmocks.Broadcaster)
+	registry := prometheus.NewRegistry()
+	sink := &fakeMetricsSink{}
+	// The order of the options does not matter, both receive the metrics
+	tracker, address := newTestTracker(t, new(mocks.Client), lb,
+		offchainreporting.WithReadOnly(),
+		offchainreporting.WithMetricsSink(sink),
+		offchainreporting.WithPrometheusRegistry(registry),
+	)
+
+	sub := newTestSubscription(t, tracker, lb)
+	topic := cltest.NewHash()
+	sub.(log.Listener).HandleLog(newBroadcast(types.Log{Address: address, Topics: []common.Hash{topic}}), nil)
+
+	require.Equal(t, float64(1), sumCounters(t, registry, offchainreporting.MetricUnrecognizedLogs))
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	require.Equal(t, []fakeMetric{
+		{offchainreporting.MetricUnrecognizedLogs, 1, map[string]string{"contract_address": address.Hex(), "topic": topic.Hex()}},
+	}, sink.metrics)
+}
+
+func Test_StatsdMetricsSink(t *testing.T) {
+	var b bytes.Buffer
+	sink := offchainreporting.NewStatsdMetricsSink(&b)
+
+	sink.IncCounter(offchainreporting.MetricUnrecognizedLogs, map[string]string{"topic": "0x01", "contract_address": "0x02"})
+	sink.SetGauge(offchainreporting.MetricSecondsSinceConfigApplied, 1.5, nil)
+
+	require.Equal(t,
+		"ocr_contract_tracker_unrecognized_logs:1|c|#contract_address:0x02,topic:0x01\n"+
+			"ocr_contract_tracker_seconds_since_config_applied:1.5|g\n",
+		b.String())
+}
@@ -0,0 +1,106 @@
+package offchainreporting
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// promMetrics are the Prometheus collectors behind promMetricsSink
+type promMetrics struct {
+	unrecognizedLogs          *prometheus.CounterVec
+	configsApplied            *prometheus.CounterVec
+	secondsSinceConfigApplied *prometheus.GaugeVec
+	activeEndpoint            *prometheus.GaugeVec
+	blockGaps                 *prometheus.CounterVec
+	configDrift               *prometheus.GaugeVec
+	oversizedLogs             *prometheus.CounterVec
+	pausedHighWater           *prometheus.CounterVec
+	linkBalance               *prometheus.GaugeVec
+}
+
+// defaultPromMetrics are registered against the default registry and shared
+// by every tracker not given WithPrometheusRegistry
+var defaultPromMetrics = newPromMetrics(prometheus.DefaultRegisterer)
+
+// newPromMetrics creates the tracker's collectors and registers them with
+// registerer. Collectors already registered by another tracker are reused, so
+// trackers may share a registry.
+func newPromMetrics(registerer prometheus.Registerer) *promMetrics {
+	return &promMetrics{
+		unrecognizedLogs: registerCounterVec(registerer, prometheus.CounterOpts{
+			Name: MetricUnrecognizedLogs,
+			Help: "Number of logs received by the OCR contract tracker with a topic it does not handle",
+		},
+			[]string{"contract_address", "topic"},
+		),
+		configsApplied: registerCounterVec(registerer, prometheus.CounterOpts{
+			Name: MetricConfigsApplied,
+			Help: "Number of configs applied by the OCR contract tracker",
+		},
+			[]string{"contract_address"},
+		),
+		secondsSinceConfigApplied: registerGaugeVec(registerer, prometheus.GaugeOpts{
+			Name: MetricSecondsSinceConfigApplied,
+			Help: "Seconds since the OCR contract tracker last applied a config, as of the latest head",
+		},
+			[]string{"contract_address"},
+		),
+		activeEndpoint: registerGaugeVec(registerer, prometheus.GaugeOpts{
+			Name: MetricActiveEndpoint,
+			Help: "Index of the RPC endpoint the OCR contract tracker is reading from, 0 being the primary",
+		},
+			[]string{"contract_address"},
+		),
+		blockGaps: registerCounterVec(registerer, prometheus.CounterOpts{
+			Name: MetricBlockGaps,
+			Help: "Number of gaps in the block numbers of logs handled by the OCR contract tracker larger than the configured threshold",
+		},
+			[]string{"contract_address"},
+		),
+		configDrift: registerGaugeVec(registerer, prometheus.GaugeOpts{
+			Name: MetricConfigDrift,
+			Help: "1 if the on-chain config digest has differed from the latest config seen by the OCR contract tracker for longer than the grace period, 0 otherwise",
+		},
+			[]string{"contract_address"},
+		),
+		oversizedLogs: registerCounterVec(registerer, prometheus.CounterOpts{
+			Name: MetricOversizedLogs,
+			Help: "Number of logs skipped by the OCR contract tracker because their data exceeded the size limit",
+		},
+			[]string{"contract_address"},
+		),
+		pausedHighWater: registerCounterVec(registerer, prometheus.CounterOpts{
+			Name: MetricPausedHighWater,
+			Help: "Number of times the logs buffered by the paused OCR contract tracker reached the high-water mark",
+		},
+			[]string{"contract_address"},
+		),
+		linkBalance: registerGaugeVec(registerer, prometheus.GaugeOpts{
+			Name: MetricLinkBalance,
+			Help: "LINK balance of the contract in juels, as of the last time the OCR contract tracker read it",
+		},
+			[]string{"contract_address"},
+		),
+	}
+}
+
+func registerCounterVec(registerer prometheus.Registerer, opts prometheus.CounterOpts, labelNames []string) *prometheus.CounterVec {
+	vec := prometheus.NewCounterVec(opts, labelNames)
+	if err := registerer.Register(vec); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector.(*prometheus.CounterVec)
+		}
+		panic(err)
+	}
+	return vec
+}
+
+func registerGaugeVec(registerer prometheus.Registerer, opts prometheus.GaugeOpts, labelNames []string) *prometheus.GaugeVec {
+	vec := prometheus.NewGaugeVec(opts, labelNames)
+	if err := registerer.Register(vec); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector.(*prometheus.GaugeVec)
+		}
+		panic(err)
+	}
+	return vec
+}
@@ -0,0 +1,44 @@
+package offchainreporting
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/smartcontractkit/chainlink/core/internal/gethwrappers/generated/offchain_aggregator_wrapper"
+)
+
+// RoundData is the decoded result of the aggregator's latestRoundData()
+type RoundData struct {
+	RoundID         *big.Int
+	Answer          *big.Int
+	StartedAt       time.Time
+	UpdatedAt       time.Time
+	AnsweredInRound *big.Int
+}
+
+// LatestRoundData returns the contract's latest round, for monitoring
+func (oc *OCRContractConfigTracker) LatestRoundData(ctx context.Context) (rd RoundData, err error) {
+	ctx, span := oc.startSpan(ctx, "LatestRoundData")
+	defer func() { endSpan(span, err) }()
+
+	opts := bind.CallOpts{Context: ctx, Pending: false}
+	var result offchain_aggregator_wrapper.LatestRoundData
+	err = oc.call(ctx, func() error {
+		return oc.withFailover(ctx, func(e endpoint) (err2 error) {
+			result, err2 = e.contract.LatestRoundData(&opts)
+			return err2
+		})
+	})
+	if err != nil {
+		return rd, oc.wrapErr(err, "error getting LatestRoundData")
+	}
+	return RoundData{
+		RoundID:         result.RoundId,
+		Answer:          result.Answer,
+		StartedAt:       time.Unix(result.StartedAt.Int64(), 0),
+		UpdatedAt:       time.Unix(result.UpdatedAt.Int64(), 0),
+		AnsweredInRound: result.AnsweredInRound,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package offchainreporting_test
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/internal/gethwrappers/generated/offchain_aggregator_wrapper"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OCRContractConfigTracker_LatestRoundData(t *testing.T) {
+	ethClient := new(mocks.Client)
+	tracker, address := newTestTracker(t, ethClient, new(logmocks.Broadcaster))
+
+	contractABI, err := abi.JSON(strings.NewReader(offchain_aggregator_wrapper.OffchainAggregatorABI))
+	require.NoError(t, err)
+	b, err := contractABI.Methods["latestRoundData"].Outputs.Pack(big.NewInt(7), big.NewInt(-42), big.NewInt(1600000000), big.NewInt(1600000060), big.NewInt(6))
+	require.NoError(t, err)
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(b, nil).Once()
+
+	rd, err := tracker.LatestRoundData(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, offchainreporting.RoundData{
+		RoundID:         big.NewInt(7),
+		Answer:          big.NewInt(-42),
+		StartedAt:       time.Unix(1600000000, 0),
+		UpdatedAt:       time.Unix(1600000060, 0),
+		AnsweredInRound: big.NewInt(6),
+	}, rd)
+
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("boom")).Once()
+	_, err = tracker.LatestRoundData(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), address.Hex())
+}
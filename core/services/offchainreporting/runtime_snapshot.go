@@ -0,0 +1,91 @@
+package offchainreporting
+
+import (
+	"encoding/hex"
+)
+
+// snapshotRecentLogBlocks is the number of block numbers of recently handled
+// logs kept for RuntimeSnapshot
+const snapshotRecentLogBlocks = 10
+
+// TrackerSnapshot is a point-in-time view of the tracker's runtime state,
+// for inclusion in node diagnostics dumps
+type TrackerSnapshot struct {
+	Subscriptions          int      `json:"subscriptions"`
+	PendingConfigs         int      `json:"pendingConfigs"`
+	RecentLogBlocks        []uint64 `json:"recentLogBlocks"`
+	LatestConfigDigest     string   `json:"latestConfigDigest,omitempty"`
+	LatestConfigBlock      uint64   `json:"latestConfigBlock,omitempty"`
+	Connected              bool     `json:"connected"`
+	Paused                 bool     `json:"paused"`
+	ActiveEndpoint         int      `json:"activeEndpoint"`
+	RPCFailures            uint64   `json:"rpcFailures"`
+	ConsecutiveRPCFailures uint32   `json:"consecutiveRpcFailures"`
+	CircuitOpen            bool     `json:"circuitOpen"`
+}
+
+// RuntimeSnapshot returns the tracker's current runtime state. Each part is
+// read under its own lock, so the parts may be from slightly different
+// moments.
+func (oc *OCRContractConfigTracker) RuntimeSnapshot() (s TrackerSnapshot) {
+	oc.subscriptionsMu.Lock()
+	s.Subscriptions = len(oc.subscriptions)
+	for sub := range oc.subscriptions {
+		s.PendingConfigs += sub.pendingConfigs()
+	}
+	oc.subscriptionsMu.Unlock()
+
+	oc.recentLogBlocksMu.Lock()
+	s.RecentLogBlocks = append([]uint64{}, oc.recentLogBlocks...)
+	oc.recentLogBlocksMu.Unlock()
+
+	oc.latestConfigMu.RLock()
+	if oc.latestConfig != nil {
+		s.LatestConfigDigest = hex.EncodeToString(oc.latestConfig.ConfigDigest[:])
+	}
+	if oc.latestConfigLog != nil {
+		s.LatestConfigBlock = oc.latestConfigLog.BlockNumber
+	}
+	oc.latestConfigMu.RUnlock()
+
+	oc.pauseMu.Lock()
+	s.Paused = oc.paused
+	oc.pauseMu.Unlock()
+
+	s.Connected = oc.IsConnected()
+	s.ActiveEndpoint = oc.ActiveEndpoint()
+	s.RPCFailures, s.ConsecutiveRPCFailures, s.CircuitOpen = oc.breaker.stats()
+	return s
+}
+
+func (oc *OCRContractConfigTracker) addSubscription(sub *OCRContractConfigSubscription) {
+	oc.subscriptionsMu.Lock()
+	defer oc.subscriptionsMu.Unlock()
+	if oc.subscriptions == nil {
+		oc.subscriptions = make(map[*OCRContractConfigSubscription]struct{})
+	}
+	oc.subscriptions[sub] = struct{}{}
+}
+
+func (oc *OCRContractConfigTracker) removeSubscription(sub *OCRContractConfigSubscription) {
+	oc.subscriptionsMu.Lock()
+	defer oc.subscriptionsMu.Unlock()
+	delete(oc.subscriptions, sub)
+}
+
+// recordHandledLog keeps the block number of the log for RuntimeSnapshot
+func (oc *OCRContractConfigTracker) recordHandledLog(blockNumber uint64) {
+	oc.recentLogBlocksMu.Lock()
+	defer oc.recentLogBlocksMu.Unlock()
+	oc.recentLogBlocks = append(oc.recentLogBlocks, blockNumber)
+	if len(oc.recentLogBlocks) > snapshotRecentLogBlocks {
+		oc.recentLogBlocks = oc.recentLogBlocks[len(oc.recentLogBlocks)-snapshotRecentLogBlocks:]
+	}
+}
+
+// pendingConfigs returns the number of configs waiting to be delivered
+func (sub *OCRContractConfigSubscription) pendingConfigs() int {
+	sub.queueMu.Lock()
+	defer sub.queueMu.Unlock()
+	return len(sub.queue)
+}
@@ -0,0 +1,63 @@
+package offchainreporting_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/log"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OCRContractConfigTracker_RuntimeSnapshot(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithCircuitBreaker(5, time.Minute))
+	require.Equal(t, offchainreporting.TrackerSnapshot{RecentLogBlocks: []uint64{}}, tracker.RuntimeSnapshot())
+
+	sub := newTestSubscription(t, tracker, lb)
+	listener := sub.(log.Listener)
+
+	listener.HandleLog(newBroadcast(types.Log{Address: address, Topics: []common.Hash{cltest.NewHash()}, BlockNumber: 5}), nil)
+	listener.HandleLog(newBroadcast(newConfigSetLog(t, address, 7, 1)), nil)
+	cc := <-sub.Configs()
+
+	ethClient.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).Return(nil, errors.New("rpc down")).Once()
+	_, err := tracker.LatestBlockHeight(context.Background())
+	require.Error(t, err)
+	tracker.Pause()
+
+	snapshot := tracker.RuntimeSnapshot()
+	require.Equal(t, offchainreporting.TrackerSnapshot{
+		Subscriptions:          1,
+		PendingConfigs:         0,
+		RecentLogBlocks:        []uint64{5, 7},
+		LatestConfigDigest:     hex.EncodeToString(cc.ConfigDigest[:]),
+		LatestConfigBlock:      7,
+		Connected:              true,
+		Paused:                 true,
+		ActiveEndpoint:         0,
+		RPCFailures:            1,
+		ConsecutiveRPCFailures: 1,
+		CircuitOpen:            false,
+	}, snapshot)
+
+	b, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"recentLogBlocks":[5,7]`)
+
+	tracker.Resume()
+	sub.Close()
+	require.Equal(t, 0, tracker.RuntimeSnapshot().Subscriptions)
+}
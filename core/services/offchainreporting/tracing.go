@@ -0,0 +1,47 @@
+package offchainreporting
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// Tracer is the subset of an OpenTelemetry tracer used by the
+	// OCRContractConfigTracker. Operators wanting distributed tracing of OCR
+	// rounds inject a thin adapter around their tracer provider using
+	// WithTracer.
+	Tracer interface {
+		Start(ctx context.Context, spanName string, attributes map[string]string) (context.Context, Span)
+	}
+
+	// Span is the subset of an OpenTelemetry span used by the tracker
+	Span interface {
+		RecordError(err error)
+		End()
+	}
+
+	noopSpan struct{}
+)
+
+func (noopSpan) RecordError(error) {}
+func (noopSpan) End()              {}
+
+// startSpan starts a span annotated with the contract address and job ID. If
+// no tracer was injected it is a no-op and returns the context unchanged.
+func (oc *OCRContractConfigTracker) startSpan(ctx context.Context, spanName string) (context.Context, Span) {
+	if oc.tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return oc.tracer.Start(ctx, spanName, map[string]string{
+		"contractAddress": oc.contract.Address().Hex(),
+		"jobID":           fmt.Sprintf("%d", oc.jobID),
+	})
+}
+
+// endSpan records err on the span, if it is not nil, and ends the span
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
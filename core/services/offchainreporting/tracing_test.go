@@ -0,0 +1,120 @@
+package offchainreporting_test
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/log"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type spanKey struct{}
+
+// endedSpan is a span ended by the code under test, with the error recorded
+// on it, if any
+type endedSpan struct {
+	name string
+	err  error
+}
+
+type fakeSpan struct {
+	tracer *fakeTracer
+	name   string
+	err    error
+}
+
+func (s *fakeSpan) RecordError(err error) {
+	s.err = err
+}
+
+func (s *fakeSpan) End() {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.tracer.ended = append(s.tracer.ended, endedSpan{s.name, s.err})
+}
+
+type fakeTracer struct {
+	mu    sync.Mutex
+	spans []string
+	attrs []map[string]string
+	ended []endedSpan
+}
+
+func (ft *fakeTracer) Start(ctx context.Context, spanName string, attributes map[string]string) (context.Context, offchainreporting.Span) {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	ft.spans = append(ft.spans, spanName)
+	ft.attrs = append(ft.attrs, attributes)
+	return context.WithValue(ctx, spanKey{}, spanName), &fakeSpan{tracer: ft, name: spanName}
+}
+
+func (ft *fakeTracer) endedSpans() []endedSpan {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	return append([]endedSpan(nil), ft.ended...)
+}
+
+// inSpan matches a context carrying the named span
+func inSpan(spanName string) interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		return ctx.Value(spanKey{}) == spanName
+	})
+}
+
+func Test_OCRContractConfigTracker_Tracer(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracer := &fakeTracer{}
+	clock := newFakeClock()
+	tracker, address := newTestTracker(t, ethClient, lb,
+		offchainreporting.WithTracer(tracer),
+		offchainreporting.WithClock(clock),
+		offchainreporting.WithDeliveryTimeout(time.Second),
+	)
+
+	ethClient.On("CallContract", inSpan("LatestConfigDetails"), mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 1, 42, [16]byte{1}), nil).Once()
+	changedInBlock, _, err := tracker.LatestConfigDetails(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), changedInBlock)
+	require.Equal(t, address.Hex(), tracer.attrs[0]["contractAddress"])
+	require.Equal(t, "42", tracer.attrs[0]["jobID"])
+
+	ethClient.On("FilterLogs", inSpan("ConfigFromLogs"), mock.Anything).Return(nil, errors.New("rpc down")).Once()
+	_, err = tracker.ConfigFromLogs(context.Background(), 42)
+	require.Error(t, err)
+
+	ethClient.On("HeaderByNumber", inSpan("LatestBlockHeight"), (*big.Int)(nil)).Return(&models.Head{Number: 43}, nil).Once()
+	height, err := tracker.LatestBlockHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(43), height)
+
+	// The first delivery times out, the retry is received
+	sub := newTestSubscription(t, tracker, lb)
+	sub.(log.Listener).HandleLog(newBroadcast(newConfigSetLog(t, address, 44, 1)), nil)
+	clock.awaitAfter(t, time.Second)
+	clock.Advance(time.Second)
+	clock.awaitAfter(t, time.Second)
+	<-sub.Configs()
+	require.Eventually(t, func() bool { return len(tracer.endedSpans()) == 5 }, 5*time.Second, 10*time.Millisecond)
+
+	ended := tracer.endedSpans()
+	require.Equal(t, []string{"LatestConfigDetails", "ConfigFromLogs", "LatestBlockHeight", "DeliverConfig", "DeliverConfig"}, tracer.spans)
+	require.Equal(t, endedSpan{"LatestConfigDetails", nil}, ended[0])
+	require.Equal(t, "ConfigFromLogs", ended[1].name)
+	require.EqualError(t, errors.Cause(ended[1].err), "rpc down")
+	require.Equal(t, endedSpan{"LatestBlockHeight", nil}, ended[2])
+	require.Equal(t, "DeliverConfig", ended[3].name)
+	require.EqualError(t, ended[3].err, "consumer did not receive config within 1s")
+	require.Equal(t, endedSpan{"DeliverConfig", nil}, ended[4])
+
+	ethClient.AssertExpectations(t)
+}
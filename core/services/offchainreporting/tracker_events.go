@@ -0,0 +1,70 @@
+package offchainreporting
+
+import (
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+)
+
+// TrackerEventType is the kind of a TrackerEvent
+type TrackerEventType string
+
+// The lifecycle events emitted by the OCRContractConfigTracker
+const (
+	// TrackerEventStarted is emitted when a subscription is started
+	TrackerEventStarted TrackerEventType = "Started"
+	// TrackerEventStopped is emitted when a subscription is closed
+	TrackerEventStopped TrackerEventType = "Stopped"
+	// TrackerEventConnected is emitted when the log broadcaster connects
+	TrackerEventConnected TrackerEventType = "Connected"
+	// TrackerEventDisconnected is emitted when the log broadcaster disconnects
+	TrackerEventDisconnected TrackerEventType = "Disconnected"
+	// TrackerEventConfigApplied is emitted when a config is delivered to
+	// libocr, or recorded by a read-only tracker
+	TrackerEventConfigApplied TrackerEventType = "ConfigApplied"
+	// TrackerEventRoundRequested is emitted when a RoundRequested log is
+	// recorded
+	TrackerEventRoundRequested TrackerEventType = "RoundRequested"
+	// TrackerEventParseError is emitted when a log fails to parse
+	TrackerEventParseError TrackerEventType = "ParseError"
+)
+
+// TrackerEvent is a lifecycle event of the tracker. ConfigDigest is set for
+// ConfigApplied and RoundRequested events, BlockNumber for RoundRequested
+// events and Err for ParseError events.
+type TrackerEvent struct {
+	Type         TrackerEventType
+	ConfigDigest ocrtypes.ConfigDigest
+	BlockNumber  uint64
+	Err          error
+}
+
+// SubscribeEvents returns a channel that receives the tracker's lifecycle
+// events. The tracker never waits on subscribers:
+// events are dropped if the subscriber falls more than
+// configListenerBufferSize events behind. The returned function
+// unsubscribes.
+func (oc *OCRContractConfigTracker) SubscribeEvents() (<-chan TrackerEvent, func()) {
+	ch := make(chan TrackerEvent, configListenerBufferSize)
+	oc.eventListenersMu.Lock()
+	defer oc.eventListenersMu.Unlock()
+	if oc.eventListeners == nil {
+		oc.eventListeners = make(map[chan TrackerEvent]struct{})
+	}
+	oc.eventListeners[ch] = struct{}{}
+	return ch, func() {
+		oc.eventListenersMu.Lock()
+		defer oc.eventListenersMu.Unlock()
+		delete(oc.eventListeners, ch)
+	}
+}
+
+func (oc *OCRContractConfigTracker) notifyEventListeners(event TrackerEvent) {
+	oc.eventListenersMu.Lock()
+	defer oc.eventListenersMu.Unlock()
+	for ch := range oc.eventListeners {
+		select {
+		case ch <- event:
+		default:
+			oc.logger.Warnw("OCRContract: event listener is full, dropping event", "event", event.Type)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package offchainreporting_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/log"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OCRContractConfigTracker_SubscribeEvents(t *testing.T) {
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	tracker, address := newTestTracker(t, ethClient, lb, offchainreporting.WithReadOnly())
+	events, unsubscribe := tracker.SubscribeEvents()
+	defer unsubscribe()
+
+	sub := newTestSubscription(t, tracker, lb)
+
+	raw := newConfigSetLog(t, address, 1, 1)
+	cc, err := offchainreporting.ParseOCRConfigSet(raw)
+	require.NoError(t, err)
+	sub.(log.Listener).HandleLog(newBroadcast(raw), nil)
+	sub.(log.Listener).OnDisconnect()
+	sub.(log.Listener).OnDisconnect()
+	sub.Close()
+
+	var got []offchainreporting.TrackerEvent
+	for len(events) > 0 {
+		got = append(got, <-events)
+	}
+	require.Equal(t, []offchainreporting.TrackerEvent{
+		{Type: offchainreporting.TrackerEventConnected},
+		{Type: offchainreporting.TrackerEventStarted},
+		{Type: offchainreporting.TrackerEventConfigApplied, ConfigDigest: cc.ConfigDigest},
+		{Type: offchainreporting.TrackerEventDisconnected},
+		{Type: offchainreporting.TrackerEventStopped},
+	}, got)
+}
@@ -0,0 +1,69 @@
+package offchainreporting
+
+import (
+	"sync"
+
+	gethCommon "github.com/ethereum/go-ethereum/common"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+)
+
+// TrackerRegistry holds the config trackers running on the node by job ID,
+// so that their views of the contracts can be compared
+type TrackerRegistry struct {
+	trackers map[int32]*OCRContractConfigTracker
+	mu       sync.RWMutex
+}
+
+// NewTrackerRegistry returns an empty TrackerRegistry
+func NewTrackerRegistry() *TrackerRegistry {
+	return &TrackerRegistry{trackers: make(map[int32]*OCRContractConfigTracker)}
+}
+
+// Register adds the tracker under its job ID, replacing any tracker
+// previously registered for the job
+func (r *TrackerRegistry) Register(oc *OCRContractConfigTracker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.trackers[oc.jobID] = oc
+}
+
+// Unregister removes the tracker of the job, if any
+func (r *TrackerRegistry) Unregister(jobID int32) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.trackers, jobID)
+}
+
+// ConfigDigests returns the digest of the latest config seen by each tracker,
+// by job ID. Trackers that have not seen a config are left out.
+func (r *TrackerRegistry) ConfigDigests() map[int32]ocrtypes.ConfigDigest {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	digests := make(map[int32]ocrtypes.ConfigDigest, len(r.trackers))
+	for jobID, oc := range r.trackers {
+		if digest, found := oc.LatestConfigDigest(); found {
+			digests[jobID] = digest
+		}
+	}
+	return digests
+}
+
+// ConfigDigestForContract returns the digest of the latest config seen by the
+// trackers of the contract. found is false unless every one of them has seen
+// a config with the same digest, so a lagging tracker is reported as
+// disagreement.
+func (r *TrackerRegistry) ConfigDigestForContract(address gethCommon.Address) (digest ocrtypes.ConfigDigest, found bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, oc := range r.trackers {
+		if oc.contract.Address() != address {
+			continue
+		}
+		trackerDigest, trackerFound := oc.LatestConfigDigest()
+		if !trackerFound || (found && trackerDigest != digest) {
+			return ocrtypes.ConfigDigest{}, false
+		}
+		digest, found = trackerDigest, true
+	}
+	return digest, found
+}
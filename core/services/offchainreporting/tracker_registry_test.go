@@ -0,0 +1,74 @@
+package offchainreporting_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/gethwrappers/generated/offchain_aggregator_wrapper"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services/log"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/smartcontractkit/libocr/gethwrappers/offchainaggregator"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TrackerRegistry_ConfigDigests(t *testing.T) {
+	address := cltest.NewAddress()
+	ethClient := new(mocks.Client)
+	lb := new(logmocks.Broadcaster)
+	lb.On("Register", mock.Anything, mock.Anything).Return(true)
+	lb.On("Unregister", mock.Anything, mock.Anything).Return()
+
+	registry := offchainreporting.NewTrackerRegistry()
+	var listeners []log.Listener
+	for jobID := int32(1); jobID <= 2; jobID++ {
+		contract, err := offchain_aggregator_wrapper.NewOffchainAggregator(address, ethClient)
+		require.NoError(t, err)
+		contractCaller, err := offchainaggregator.NewOffchainAggregatorCaller(address, ethClient)
+		require.NoError(t, err)
+		tracker, err := offchainreporting.NewOCRContractConfigTracker(contract, contractCaller, ethClient, lb, jobID, *logger.Default, offchainreporting.WithReadOnly())
+		require.NoError(t, err)
+		sub, err := tracker.SubscribeToNewConfigs(context.Background())
+		require.NoError(t, err)
+		defer sub.Close()
+		registry.Register(tracker)
+		listeners = append(listeners, sub.(log.Listener))
+	}
+
+	_, found := registry.ConfigDigestForContract(address)
+	require.False(t, found)
+	require.Empty(t, registry.ConfigDigests())
+
+	first := newConfigSetLog(t, address, 1, 1)
+	cc, err := offchainreporting.ParseOCRConfigSet(first)
+	require.NoError(t, err)
+	for _, listener := range listeners {
+		listener.HandleLog(newBroadcast(first), nil)
+	}
+	require.Equal(t, map[int32]ocrtypes.ConfigDigest{1: cc.ConfigDigest, 2: cc.ConfigDigest}, registry.ConfigDigests())
+	digest, found := registry.ConfigDigestForContract(address)
+	require.True(t, found)
+	require.Equal(t, cc.ConfigDigest, digest)
+
+	// Job 2 sees a newer config that job 1 has not caught up with
+	second := newConfigSetLog(t, address, 2, 2)
+	newer, err := offchainreporting.ParseOCRConfigSet(second)
+	require.NoError(t, err)
+	listeners[1].HandleLog(newBroadcast(second), nil)
+	require.Equal(t, map[int32]ocrtypes.ConfigDigest{1: cc.ConfigDigest, 2: newer.ConfigDigest}, registry.ConfigDigests())
+	_, found = registry.ConfigDigestForContract(address)
+	require.False(t, found)
+
+	registry.Unregister(1)
+	digest, found = registry.ConfigDigestForContract(address)
+	require.True(t, found)
+	require.Equal(t, newer.ConfigDigest, digest)
+
+	_, found = registry.ConfigDigestForContract(cltest.NewAddress())
+	require.False(t, found)
+}
@@ -0,0 +1,64 @@
+package offchainreporting
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/smartcontractkit/chainlink/core/utils"
+)
+
+var (
+	typeAndVersionSelector = utils.MustHash("typeAndVersion()").Bytes()[:4]
+	typeAndVersionOutputs  = abi.Arguments{{Type: mustNewType("string")}}
+)
+
+func mustNewType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// DetectTypeAndVersion calls typeAndVersion() on the contract. Older
+// OffchainAggregator deployments do not implement it, in which case an error
+// is returned.
+func (oc *OCRContractConfigTracker) DetectTypeAndVersion(ctx context.Context) (string, error) {
+	address := oc.contract.Address()
+	var out []byte
+	err := oc.call(ctx, func() error {
+		return oc.withFailover(ctx, func(e endpoint) (err2 error) {
+			out, err2 = e.client.CallContract(ctx, ethereum.CallMsg{To: &address, Data: typeAndVersionSelector}, nil)
+			return err2
+		})
+	})
+	if err != nil {
+		return "", oc.wrapErr(err, "could not call typeAndVersion")
+	}
+	values, err := typeAndVersionOutputs.Unpack(out)
+	if err != nil {
+		return "", oc.wrapErr(err, "could not decode typeAndVersion")
+	}
+	typeAndVersion, ok := values[0].(string)
+	if !ok {
+		return "", oc.errorf("unexpected typeAndVersion %v of type %T", values[0], values[0])
+	}
+	return typeAndVersion, nil
+}
+
+func (oc *OCRContractConfigTracker) checkTypeAndVersion(ctx context.Context) error {
+	if len(oc.expectedTypeAndVersions) == 0 {
+		return nil
+	}
+	typeAndVersion, err := oc.DetectTypeAndVersion(ctx)
+	if err != nil {
+		return err
+	}
+	for _, expected := range oc.expectedTypeAndVersions {
+		if typeAndVersion == expected {
+			return nil
+		}
+	}
+	return oc.errorf("contract has typeAndVersion %q, expected one of %q", typeAndVersion, oc.expectedTypeAndVersions)
+}
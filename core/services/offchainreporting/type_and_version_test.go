@@ -0,0 +1,56 @@
+package offchainreporting_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/gethwrappers/generated/offchain_aggregator_wrapper"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	logmocks "github.com/smartcontractkit/chainlink/core/services/log/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/smartcontractkit/libocr/gethwrappers/offchainaggregator"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OCRContractConfigTracker_DetectTypeAndVersion(t *testing.T) {
+	address := cltest.NewAddress()
+	ethClient := new(mocks.Client)
+	contract, err := offchain_aggregator_wrapper.NewOffchainAggregator(address, ethClient)
+	require.NoError(t, err)
+	contractCaller, err := offchainaggregator.NewOffchainAggregatorCaller(address, ethClient)
+	require.NoError(t, err)
+
+	stringType, err := abi.NewType("string", "", nil)
+	require.NoError(t, err)
+	encodedVersion, err := abi.Arguments{{Type: stringType}}.Pack("AccessControlledOffchainAggregator 3.0.0")
+	require.NoError(t, err)
+
+	isTypeAndVersionCall := mock.MatchedBy(func(msg ethereum.CallMsg) bool {
+		return bytes.Equal(msg.Data, crypto.Keccak256([]byte("typeAndVersion()"))[:4])
+	})
+	ethClient.On("CodeAt", mock.Anything, address, (*big.Int)(nil)).Return([]byte{1}, nil)
+	ethClient.On("CallContract", mock.Anything, isTypeAndVersionCall, (*big.Int)(nil)).Return(encodedVersion, nil)
+	ethClient.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(mustEncodeLatestConfigDetails(t, 1, 42, [16]byte{1}), nil)
+
+	tracker, err := offchainreporting.NewOCRContractConfigTrackerChecked(context.Background(), contract, contractCaller, ethClient, new(logmocks.Broadcaster), 42, *logger.Default,
+		offchainreporting.WithExpectedTypeAndVersions("AccessControlledOffchainAggregator 3.0.0"),
+	)
+	require.NoError(t, err)
+	typeAndVersion, err := tracker.DetectTypeAndVersion(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "AccessControlledOffchainAggregator 3.0.0", typeAndVersion)
+
+	_, err = offchainreporting.NewOCRContractConfigTrackerChecked(context.Background(), contract, contractCaller, ethClient, new(logmocks.Broadcaster), 42, *logger.Default,
+		offchainreporting.WithExpectedTypeAndVersions("AccessControlledOffchainAggregator 2.0.0"),
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `has typeAndVersion "AccessControlledOffchainAggregator 3.0.0"`)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1611388693"
 	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1611847145"
 	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1612225637"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1612440000"
 
 	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1608217193"
 
@@ -508,6 +509,10 @@ func init() {
 			ID:      "1612225637",
 			Migrate: migration1612225637.Migrate,
 		},
+		{
+			ID:      "1612440000",
+			Migrate: migration1612440000.Migrate,
+		},
 	}
 }
 
@@ -0,0 +1,24 @@
+package migration1612440000
+
+import "github.com/jinzhu/gorm"
+
+// Migrate adds a history of every contract config applied by an OCR job
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE offchainreporting_contract_config_history (
+		id BIGSERIAL PRIMARY KEY,
+		offchainreporting_oracle_spec_id INT NOT NULL REFERENCES offchainreporting_oracle_specs (id) ON DELETE CASCADE,
+		config_digest bytea NOT NULL CHECK (octet_length(config_digest) = 16),
+		signers bytea[],
+		transmitters bytea[],
+		threshold integer,
+		encoded_config_version bigint,
+		encoded bytea,
+		block_number bigint NOT NULL,
+		applied_at timestamptz NOT NULL,
+		created_at timestamptz NOT NULL
+	);
+
+	CREATE INDEX idx_offchainreporting_contract_config_history_applied_at ON offchainreporting_contract_config_history (offchainreporting_oracle_spec_id, applied_at);
+	`).Error
+}
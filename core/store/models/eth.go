@@ -3,11 +3,13 @@ package models
 import (
 	"bytes"
 	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
 	"regexp"
+	"strings"
 	"time"
 
 	uuid "github.com/satori/go.uuid"
@@ -15,9 +17,11 @@ import (
 	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/utils"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -239,6 +243,106 @@ var WeiPerEth = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
 
 type Log = types.Log
 
+// EVMWordLength is the size in bytes of a single ABI-encoded data slot
+const EVMWordLength = 32
+
+// LogDataWordAt returns the 32 byte word at the given slot index of the log's
+// data, erroring instead of panicking if the data is too short
+func LogDataWordAt(log Log, index int) (common.Hash, error) {
+	word, err := UntrustedBytes(log.Data).SafeByteSlice(index*EVMWordLength, (index+1)*EVMWordLength)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("could not read data word %d from log of length %d: %v", index, len(log.Data), err)
+	}
+	return common.BytesToHash(word), nil
+}
+
+// LogDataBigIntAt returns the 32 byte word at the given slot index of the
+// log's data interpreted as an unsigned big-endian integer
+func LogDataBigIntAt(log Log, index int) (*big.Int, error) {
+	word, err := LogDataWordAt(log, index)
+	if err != nil {
+		return nil, err
+	}
+	return word.Big(), nil
+}
+
+// LogDataUint64At returns the 32 byte word at the given slot index of the
+// log's data as a uint64, erroring rather than truncating if it does not fit
+func LogDataUint64At(log Log, index int) (uint64, error) {
+	n, err := LogDataBigIntAt(log, index)
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsUint64() {
+		return 0, fmt.Errorf("data word %d of log is %s, which exceeds the maximum uint64", index, n)
+	}
+	return n.Uint64(), nil
+}
+
+// LogTopicAt returns the topic at the given index of the log, erroring
+// instead of panicking if the log has too few topics
+func LogTopicAt(log Log, index int) (common.Hash, error) {
+	if index < 0 || index >= len(log.Topics) {
+		return common.Hash{}, fmt.Errorf("could not read topic %d from log with %d topics", index, len(log.Topics))
+	}
+	return log.Topics[index], nil
+}
+
+// LogTopicAsFunctionSelector returns the function selector indexed in the
+// topic at the given index of the log. Indexed bytes4 values are left-aligned
+// in their topic, so the selector is the topic's first 4 bytes.
+func LogTopicAsFunctionSelector(log Log, index int) (FunctionSelector, error) {
+	topic, err := LogTopicAt(log, index)
+	if err != nil {
+		return FunctionSelector{}, err
+	}
+	return BytesToFunctionSelector(topic.Bytes()), nil
+}
+
+// LogIndexedAddresses returns the count addresses indexed in consecutive
+// topics of the log, starting at startTopic. Each address is the last 20
+// bytes of its topic.
+func LogIndexedAddresses(log Log, startTopic int, count int) ([]common.Address, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("invalid indexed address count %d", count)
+	}
+	addresses := make([]common.Address, count)
+	for i := range addresses {
+		topic, err := LogTopicAt(log, startTopic+i)
+		if err != nil {
+			return nil, err
+		}
+		addresses[i] = common.BytesToAddress(topic.Bytes())
+	}
+	return addresses, nil
+}
+
+// LogCanonicalBytes serializes the consensus fields of a log (address,
+// topics and data) in a fixed, length-prefixed order. Derived fields such as
+// the block hash and log index are excluded, so the same log included in
+// different blocks serializes identically.
+func LogCanonicalBytes(log Log) []byte {
+	b := make([]byte, 0, common.AddressLength+8+len(log.Topics)*common.HashLength+8+len(log.Data))
+	b = append(b, log.Address.Bytes()...)
+	b = append(b, uint64ToBytes(uint64(len(log.Topics)))...)
+	for _, topic := range log.Topics {
+		b = append(b, topic.Bytes()...)
+	}
+	b = append(b, uint64ToBytes(uint64(len(log.Data)))...)
+	return append(b, log.Data...)
+}
+
+// LogContentHash returns the keccak256 hash of LogCanonicalBytes
+func LogContentHash(log Log) common.Hash {
+	return crypto.Keccak256Hash(LogCanonicalBytes(log))
+}
+
+func uint64ToBytes(n uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, n)
+	return b
+}
+
 var emptyHash = common.Hash{}
 
 // Unconfirmed returns true if the transaction is not confirmed.
@@ -262,6 +366,68 @@ func ReceiptIndicatesRunLogFulfillment(txr types.Receipt) bool {
 	return false
 }
 
+// FilterFulfilledReceipts returns the tx receipts that are the result of a
+// fulfilled run log, in order.
+func FilterFulfilledReceipts(receipts []types.Receipt) []types.Receipt {
+	var fulfilled []types.Receipt
+	for _, txr := range receipts {
+		if ReceiptIndicatesRunLogFulfillment(txr) {
+			fulfilled = append(fulfilled, txr)
+		}
+	}
+	return fulfilled
+}
+
+// MapFulfilledRequestIDs returns the hash of the fulfilling tx for each run
+// log request ID fulfilled in the tx receipts.
+func MapFulfilledRequestIDs(receipts []types.Receipt) (map[common.Hash]common.Hash, error) {
+	fulfillments := make(map[common.Hash]common.Hash)
+	for _, txr := range receipts {
+		for _, log := range txr.Logs {
+			if len(log.Topics) == 0 || log.Topics[0] != ChainlinkFulfilledTopic {
+				continue
+			}
+			if len(log.Topics) != 2 {
+				return nil, fmt.Errorf("ChainlinkFulfilled log %d in tx %s has %d topics, expected 2", log.Index, txr.TxHash.Hex(), len(log.Topics))
+			}
+			fulfillments[log.Topics[1]] = txr.TxHash
+		}
+	}
+	return fulfillments, nil
+}
+
+// ChainlinkRequestedTopic is the signature for the event emitted after calling
+// ChainlinkClient.sendChainlinkRequestTo. See
+// ../../evm-contracts/src/v0.6/ChainlinkClient.sol
+var ChainlinkRequestedTopic = utils.MustHash("ChainlinkRequested(bytes32)")
+
+// ReceiptIndicatesRunLogRequest returns true if this tx receipt contains a
+// run log request.
+func ReceiptIndicatesRunLogRequest(txr types.Receipt) bool {
+	for _, log := range txr.Logs {
+		if len(log.Topics) > 0 && log.Topics[0] == ChainlinkRequestedTopic {
+			return true
+		}
+	}
+	return false
+}
+
+// ReceiptRequestIDs returns the IDs of the run log requests in this tx
+// receipt, in log order.
+func ReceiptRequestIDs(txr types.Receipt) ([]common.Hash, error) {
+	var ids []common.Hash
+	for _, log := range txr.Logs {
+		if len(log.Topics) == 0 || log.Topics[0] != ChainlinkRequestedTopic {
+			continue
+		}
+		if len(log.Topics) != 2 {
+			return nil, fmt.Errorf("ChainlinkRequested log %d in tx %s has %d topics, expected 2", log.Index, txr.TxHash.Hex(), len(log.Topics))
+		}
+		ids = append(ids, log.Topics[1])
+	}
+	return ids, nil
+}
+
 // FunctionSelector is the first four bytes of the call data for a
 // function call and specifies the function to be called.
 type FunctionSelector [FunctionSelectorLength]byte
@@ -290,6 +456,23 @@ func (f FunctionSelector) Bytes() []byte { return f[:] }
 // SetBytes sets the FunctionSelector to that of the given bytes (will trim).
 func (f *FunctionSelector) SetBytes(b []byte) { copy(f[:], b[:FunctionSelectorLength]) }
 
+// MatchMethod returns the method of the given ABI whose ID is f, if any
+func (f FunctionSelector) MatchMethod(a abi.ABI) (*abi.Method, bool) {
+	for _, method := range a.Methods {
+		if bytes.Equal(method.ID, f[:]) {
+			method := method
+			return &method, true
+		}
+	}
+	return nil, false
+}
+
+// EventTopicFromSignature returns the topic0 of an event from its signature,
+// e.g. "Transfer(address,address,uint256)". Whitespace is ignored.
+func EventTopicFromSignature(sig string) common.Hash {
+	return utils.MustHash(strings.Join(strings.Fields(sig), ""))
+}
+
 var hexRegexp *regexp.Regexp = regexp.MustCompile("^[0-9a-fA-F]*$")
 
 func unmarshalFromString(s string, f *FunctionSelector) error {
@@ -333,8 +516,13 @@ func (f FunctionSelector) Value() (driver.Value, error) {
 	return f.Bytes(), nil
 }
 
-// Scan returns the selector from its serialization in the database
+// Scan returns the selector from its serialization in the database. A NULL
+// column scans as the zero selector.
 func (f *FunctionSelector) Scan(value interface{}) error {
+	if value == nil {
+		*f = FunctionSelector{}
+		return nil
+	}
 	temp, ok := value.([]byte)
 	if !ok {
 		return fmt.Errorf("unable to convent %v of type %T to FunctionSelector", value, value)
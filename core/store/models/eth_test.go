@@ -7,15 +7,18 @@ import (
 	"math"
 	"math/big"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/smartcontractkit/chainlink/core/assets"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/gethwrappers/generated/offchain_aggregator_wrapper"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/store/presenters"
 	"github.com/smartcontractkit/chainlink/core/utils"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	gethTypes "github.com/ethereum/go-ethereum/core/types"
@@ -206,6 +209,46 @@ func TestModels_FunctionSelectorUnmarshalJSONError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestModels_FunctionSelectorScan(t *testing.T) {
+	t.Parallel()
+
+	fid := models.HexToFunctionSelector("0xb3f98adc")
+	require.NoError(t, fid.Scan(nil))
+	assert.Equal(t, models.FunctionSelector{}, fid)
+
+	require.NoError(t, fid.Scan([]byte{0xb3, 0xf9, 0x8a, 0xdc}))
+	assert.Equal(t, "0xb3f98adc", fid.String())
+
+	assert.Error(t, fid.Scan([]byte{0xb3, 0xf9, 0x8a}))
+	assert.Error(t, fid.Scan([]byte{0xb3, 0xf9, 0x8a, 0xdc, 0x12}))
+	assert.Error(t, fid.Scan("0xb3f98adc"))
+	assert.Equal(t, "0xb3f98adc", fid.String())
+}
+
+func TestModels_EventTopicFromSignature(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(offchain_aggregator_wrapper.OffchainAggregatorABI))
+	require.NoError(t, err)
+	configSetTopic := contractABI.Events["ConfigSet"].ID
+
+	assert.Equal(t, configSetTopic, models.EventTopicFromSignature("ConfigSet(uint32,uint64,address[],address[],uint8,uint64,bytes)"))
+	assert.Equal(t, configSetTopic, models.EventTopicFromSignature("ConfigSet(uint32, uint64, address[], address[], uint8, uint64, bytes)"))
+	assert.NotEqual(t, configSetTopic, models.EventTopicFromSignature("ConfigSet(uint32,uint64)"))
+}
+
+func TestModels_FunctionSelectorMatchMethod(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(offchain_aggregator_wrapper.OffchainAggregatorABI))
+	require.NoError(t, err)
+
+	for _, name := range []string{"latestConfigDetails", "latestRoundData", "transmit"} {
+		method, found := models.BytesToFunctionSelector(contractABI.Methods[name].ID).MatchMethod(contractABI)
+		require.True(t, found)
+		assert.Equal(t, name, method.Name)
+	}
+
+	_, found := models.HexToFunctionSelector("0xdeadbeef").MatchMethod(contractABI)
+	assert.False(t, found)
+}
+
 func TestSafeByteSlice_Success(t *testing.T) {
 	tests := []struct {
 		ary      models.UntrustedBytes
@@ -250,6 +293,108 @@ func TestSafeByteSlice_Error(t *testing.T) {
 	}
 }
 
+func TestLogDataWordAt(t *testing.T) {
+	log := models.Log{Data: append(common.BigToHash(big.NewInt(7)).Bytes(), common.BigToHash(big.NewInt(42)).Bytes()...)}
+
+	word, err := models.LogDataWordAt(log, 1)
+	require.NoError(t, err)
+	assert.Equal(t, common.BigToHash(big.NewInt(42)), word)
+
+	n, err := models.LogDataBigIntAt(log, 0)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(7), n)
+
+	log.Data = log.Data[:40]
+	_, err = models.LogDataWordAt(log, 1)
+	assert.Error(t, err)
+	_, err = models.LogDataBigIntAt(log, 1)
+	assert.Error(t, err)
+	_, err = models.LogDataWordAt(log, -1)
+	assert.Error(t, err)
+}
+
+func TestLogDataUint64At(t *testing.T) {
+	maxUint64 := new(big.Int).SetUint64(math.MaxUint64)
+	overflow := new(big.Int).Add(maxUint64, big.NewInt(1))
+	log := models.Log{Data: append(append(
+		common.BigToHash(big.NewInt(7)).Bytes(),
+		common.BigToHash(maxUint64).Bytes()...),
+		common.BigToHash(overflow).Bytes()...),
+	}
+
+	n, err := models.LogDataUint64At(log, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), n)
+
+	n, err = models.LogDataUint64At(log, 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(math.MaxUint64), n)
+
+	_, err = models.LogDataUint64At(log, 2)
+	assert.Error(t, err)
+	_, err = models.LogDataUint64At(log, 3)
+	assert.Error(t, err)
+}
+
+func TestLogIndexedAddresses(t *testing.T) {
+	a, b := cltest.NewAddress(), cltest.NewAddress()
+	log := models.Log{Topics: []common.Hash{cltest.NewHash(), a.Hash(), b.Hash()}}
+
+	addresses, err := models.LogIndexedAddresses(log, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{a, b}, addresses)
+
+	addresses, err = models.LogIndexedAddresses(log, 2, 0)
+	require.NoError(t, err)
+	assert.Empty(t, addresses)
+
+	_, err = models.LogIndexedAddresses(log, 2, 2)
+	assert.Error(t, err)
+	_, err = models.LogIndexedAddresses(log, -1, 1)
+	assert.Error(t, err)
+	_, err = models.LogIndexedAddresses(log, 1, -1)
+	assert.Error(t, err)
+}
+
+func TestLogTopicAsFunctionSelector(t *testing.T) {
+	selector := models.HexToFunctionSelector("0xa9059cbb")
+	var topic common.Hash
+	copy(topic[:], selector.Bytes())
+	log := models.Log{Topics: []common.Hash{cltest.NewHash(), topic}}
+
+	got, err := models.LogTopicAsFunctionSelector(log, 1)
+	require.NoError(t, err)
+	assert.Equal(t, selector, got)
+
+	_, err = models.LogTopicAsFunctionSelector(log, 2)
+	assert.Error(t, err)
+}
+
+func TestLogContentHash(t *testing.T) {
+	log := models.Log{
+		Address:     cltest.NewAddress(),
+		Topics:      []common.Hash{cltest.NewHash(), cltest.NewHash()},
+		Data:        []byte{1, 2, 3},
+		BlockNumber: 1,
+		BlockHash:   cltest.NewHash(),
+		TxHash:      cltest.NewHash(),
+		Index:       1,
+	}
+	reincluded := log
+	reincluded.BlockNumber = 2
+	reincluded.BlockHash = cltest.NewHash()
+	reincluded.Index = 5
+
+	assert.Equal(t, models.LogCanonicalBytes(log), models.LogCanonicalBytes(reincluded))
+	assert.Equal(t, models.LogContentHash(log), models.LogContentHash(reincluded))
+
+	// Moving a topic into the data changes the hash
+	shifted := log
+	shifted.Topics = log.Topics[:1]
+	shifted.Data = append(log.Topics[1].Bytes(), log.Data...)
+	assert.NotEqual(t, models.LogContentHash(log), models.LogContentHash(shifted))
+}
+
 func TestHead_EarliestInChain(t *testing.T) {
 	head := models.Head{
 		Number: 3,
@@ -284,6 +429,59 @@ func TestTxReceipt_ReceiptIndicatesRunLogFulfillment(t *testing.T) {
 	}
 }
 
+func TestTxReceipt_FulfilledReceipts(t *testing.T) {
+	basic := cltest.TxReceiptFromFixture(t, "../../services/eth/testdata/getTransactionReceipt.json")
+	request := cltest.TxReceiptFromFixture(t, "../../services/eth/testdata/runlogReceipt.json")
+	response := cltest.TxReceiptFromFixture(t, "../../services/eth/testdata/responseReceipt.json")
+	requestID := cltest.NewHash()
+	otherResponse := gethTypes.Receipt{
+		TxHash: cltest.NewHash(),
+		Logs:   []*gethTypes.Log{{Topics: []common.Hash{models.ChainlinkFulfilledTopic, requestID}}},
+	}
+	receipts := []gethTypes.Receipt{*basic, *response, *request, otherResponse}
+
+	require.Equal(t, []gethTypes.Receipt{*response, otherResponse}, models.FilterFulfilledReceipts(receipts))
+	require.Empty(t, models.FilterFulfilledReceipts([]gethTypes.Receipt{*basic, *request}))
+
+	fulfillments, err := models.MapFulfilledRequestIDs(receipts)
+	require.NoError(t, err)
+	require.Equal(t, map[common.Hash]common.Hash{
+		common.HexToHash("0xc524fafafcaec40652b1f84fca09c231185437d008d195fccf2f51e64b7062f8"): response.TxHash,
+		requestID: otherResponse.TxHash,
+	}, fulfillments)
+
+	malformed := gethTypes.Receipt{Logs: []*gethTypes.Log{{Topics: []common.Hash{models.ChainlinkFulfilledTopic}}}}
+	_, err = models.MapFulfilledRequestIDs(append(receipts, malformed))
+	require.Error(t, err)
+}
+
+func TestTxReceipt_ReceiptIndicatesRunLogRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want []common.Hash
+	}{
+		{"basic", "../../services/eth/testdata/getTransactionReceipt.json", nil},
+		{"runlog request", "../../services/eth/testdata/runlogReceipt.json", []common.Hash{common.HexToHash("0xc524fafafcaec40652b1f84fca09c231185437d008d195fccf2f51e64b7062f8")}},
+		{"runlog response", "../../services/eth/testdata/responseReceipt.json", nil},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			receipt := cltest.TxReceiptFromFixture(t, test.path)
+			require.Equal(t, len(test.want) > 0, models.ReceiptIndicatesRunLogRequest(*receipt))
+			ids, err := models.ReceiptRequestIDs(*receipt)
+			require.NoError(t, err)
+			require.Equal(t, test.want, ids)
+		})
+	}
+
+	malformed := gethTypes.Receipt{Logs: []*gethTypes.Log{{Topics: []common.Hash{models.ChainlinkRequestedTopic}}}}
+	_, err := models.ReceiptRequestIDs(malformed)
+	require.Error(t, err)
+}
+
 func TestHead_UnmarshalJSON(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,192 @@
+package models
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// OCRConfigEncodedVersion is the only version of the ConfigSet encoded blob
+// understood by DecodeOCRConfigEncoded
+const OCRConfigEncodedVersion = 1
+
+// OCRConfigParams are the offchain oracle parameters contained in the
+// encoded blob of an OffchainAggregator ConfigSet event. The shared secret
+// is not decoded.
+type OCRConfigParams struct {
+	DeltaProgress time.Duration
+	DeltaResend   time.Duration
+	DeltaRound    time.Duration
+	DeltaGrace    time.Duration
+	DeltaC        time.Duration
+	AlphaPPB      uint64
+	DeltaStage    time.Duration
+	RMax          uint8
+	S             []int
+	PeerIDs       []string
+}
+
+// Word indices of the head of the version 1 encoded tuple
+const (
+	ocrConfigDeltaProgressWord = iota
+	ocrConfigDeltaResendWord
+	ocrConfigDeltaRoundWord
+	ocrConfigDeltaGraceWord
+	ocrConfigDeltaCWord
+	ocrConfigAlphaPPBWord
+	ocrConfigDeltaStageWord
+	ocrConfigRMaxWord
+	ocrConfigSWord
+	_ // offchainPublicKeys
+	ocrConfigPeerIDsWord
+)
+
+var (
+	twoTo255 = new(big.Int).Lsh(big.NewInt(1), 255)
+	twoTo256 = new(big.Int).Lsh(big.NewInt(1), 256)
+)
+
+// DecodeOCRConfigEncoded decodes the encoded blob of a ConfigSet event. The
+// blob is user-supplied, so every offset and length is bounds-checked and an
+// error is returned on malformed or truncated input instead of panicking.
+func DecodeOCRConfigEncoded(encoded UntrustedBytes, version uint64) (params OCRConfigParams, err error) {
+	if version != OCRConfigEncodedVersion {
+		return params, errors.Errorf("unsupported encoded config version %d", version)
+	}
+	// The blob is a single ABI-encoded dynamic tuple, so it starts with the
+	// offset of the tuple
+	start, err := encoded.offsetAt(0)
+	if err != nil {
+		return params, errors.Wrap(err, "could not read tuple offset")
+	}
+	tuple, err := encoded.SafeByteSlice(start, len(encoded))
+	if err != nil {
+		return params, errors.Wrap(err, "could not read tuple")
+	}
+	head := UntrustedBytes(tuple)
+
+	durations := []struct {
+		word int
+		dest *time.Duration
+	}{
+		{ocrConfigDeltaProgressWord, &params.DeltaProgress},
+		{ocrConfigDeltaResendWord, &params.DeltaResend},
+		{ocrConfigDeltaRoundWord, &params.DeltaRound},
+		{ocrConfigDeltaGraceWord, &params.DeltaGrace},
+		{ocrConfigDeltaCWord, &params.DeltaC},
+		{ocrConfigDeltaStageWord, &params.DeltaStage},
+	}
+	for _, d := range durations {
+		n, err2 := head.int64At(d.word)
+		if err2 != nil {
+			return params, errors.Wrapf(err2, "could not read duration at word %d", d.word)
+		}
+		*d.dest = time.Duration(n)
+	}
+	if params.AlphaPPB, err = head.uint64At(ocrConfigAlphaPPBWord); err != nil {
+		return params, errors.Wrap(err, "could not read alphaPPB")
+	}
+	rMax, err := head.uint64At(ocrConfigRMaxWord)
+	if err != nil {
+		return params, errors.Wrap(err, "could not read rMax")
+	} else if rMax > 255 {
+		return params, errors.Errorf("rMax %d does not fit in a uint8", rMax)
+	}
+	params.RMax = uint8(rMax)
+
+	sOffset, err := head.offsetAt(ocrConfigSWord)
+	if err != nil {
+		return params, errors.Wrap(err, "could not read offset of s")
+	}
+	sLen, err := head.lengthAt(sOffset)
+	if err != nil {
+		return params, errors.Wrap(err, "could not read length of s")
+	}
+	for i := 0; i < sLen; i++ {
+		n, err2 := head.uint64At(sOffset/EVMWordLength + 1 + i)
+		if err2 != nil {
+			return params, errors.Wrapf(err2, "could not read s[%d]", i)
+		} else if n > 255 {
+			return params, errors.Errorf("s[%d] = %d does not fit in a uint8", i, n)
+		}
+		params.S = append(params.S, int(n))
+	}
+
+	peerIDsOffset, err := head.offsetAt(ocrConfigPeerIDsWord)
+	if err != nil {
+		return params, errors.Wrap(err, "could not read offset of peerIDs")
+	}
+	peerIDsLen, err := head.lengthAt(peerIDsOffset)
+	if err != nil {
+		return params, errors.Wrap(err, "could not read length of peerIDs")
+	}
+	peerIDs, err := head.SafeByteSlice(peerIDsOffset+EVMWordLength, peerIDsOffset+EVMWordLength+peerIDsLen)
+	if err != nil {
+		return params, errors.Wrap(err, "could not read peerIDs")
+	}
+	if len(peerIDs) > 0 {
+		params.PeerIDs = strings.Split(string(peerIDs), ",")
+	}
+	return params, nil
+}
+
+func (ary UntrustedBytes) bigIntAt(index int) (*big.Int, error) {
+	word, err := ary.SafeByteSlice(index*EVMWordLength, (index+1)*EVMWordLength)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read word %d of %d bytes", index, len(ary))
+	}
+	return new(big.Int).SetBytes(word), nil
+}
+
+func (ary UntrustedBytes) uint64At(index int) (uint64, error) {
+	n, err := ary.bigIntAt(index)
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsUint64() {
+		return 0, errors.Errorf("word %d does not fit in a uint64", index)
+	}
+	return n.Uint64(), nil
+}
+
+func (ary UntrustedBytes) int64At(index int) (int64, error) {
+	n, err := ary.bigIntAt(index)
+	if err != nil {
+		return 0, err
+	}
+	if n.Cmp(twoTo255) >= 0 {
+		n.Sub(n, twoTo256)
+	}
+	if !n.IsInt64() {
+		return 0, errors.Errorf("word %d does not fit in an int64", index)
+	}
+	return n.Int64(), nil
+}
+
+// offsetAt reads the byte offset stored at the given word and checks that it
+// is word-aligned and within bounds
+func (ary UntrustedBytes) offsetAt(index int) (int, error) {
+	offset, err := ary.uint64At(index)
+	if err != nil {
+		return 0, err
+	}
+	if offset%EVMWordLength != 0 || offset >= uint64(len(ary)) {
+		return 0, errors.Errorf("invalid offset %d into %d bytes", offset, len(ary))
+	}
+	return int(offset), nil
+}
+
+// lengthAt reads the length prefix of the dynamic value at the given byte
+// offset
+func (ary UntrustedBytes) lengthAt(offset int) (int, error) {
+	length, err := ary.uint64At(offset / EVMWordLength)
+	if err != nil {
+		return 0, err
+	}
+	if length > uint64(len(ary)) {
+		return 0, errors.Errorf("length %d exceeds %d bytes", length, len(ary))
+	}
+	return int(length), nil
+}
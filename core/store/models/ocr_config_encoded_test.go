@@ -0,0 +1,99 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sharedSecretEncryptions struct {
+	DiffieHellmanPoint [32]byte
+	SharedSecretHash   [32]byte
+	Encryptions        [][16]byte
+}
+
+type setConfigEncodedComponents struct {
+	DeltaProgress           int64
+	DeltaResend             int64
+	DeltaRound              int64
+	DeltaGrace              int64
+	DeltaC                  int64
+	AlphaPPB                uint64
+	DeltaStage              int64
+	RMax                    uint8
+	S                       []uint8
+	OffchainPublicKeys      [][32]byte
+	PeerIDs                 string
+	SharedSecretEncryptions sharedSecretEncryptions
+}
+
+func mustEncodeOCRConfig(t *testing.T, c setConfigEncodedComponents) []byte {
+	tupleType, err := abi.NewType("tuple", "", []abi.ArgumentMarshaling{
+		{Name: "deltaProgress", Type: "int64"},
+		{Name: "deltaResend", Type: "int64"},
+		{Name: "deltaRound", Type: "int64"},
+		{Name: "deltaGrace", Type: "int64"},
+		{Name: "deltaC", Type: "int64"},
+		{Name: "alphaPPB", Type: "uint64"},
+		{Name: "deltaStage", Type: "int64"},
+		{Name: "rMax", Type: "uint8"},
+		{Name: "s", Type: "uint8[]"},
+		{Name: "offchainPublicKeys", Type: "bytes32[]"},
+		{Name: "peerIDs", Type: "string"},
+		{Name: "sharedSecretEncryptions", Type: "tuple", Components: []abi.ArgumentMarshaling{
+			{Name: "diffieHellmanPoint", Type: "bytes32"},
+			{Name: "sharedSecretHash", Type: "bytes32"},
+			{Name: "encryptions", Type: "bytes16[]"},
+		}},
+	})
+	require.NoError(t, err)
+	b, err := abi.Arguments{{Name: "config", Type: tupleType}}.Pack(c)
+	require.NoError(t, err)
+	return b
+}
+
+func TestDecodeOCRConfigEncoded(t *testing.T) {
+	encoded := mustEncodeOCRConfig(t, setConfigEncodedComponents{
+		DeltaProgress:      int64(10 * time.Second),
+		DeltaResend:        int64(5 * time.Second),
+		DeltaRound:         int64(3 * time.Second),
+		DeltaGrace:         int64(500 * time.Millisecond),
+		DeltaC:             int64(time.Minute),
+		AlphaPPB:           1000000,
+		DeltaStage:         int64(20 * time.Second),
+		RMax:               7,
+		S:                  []uint8{1, 1, 2},
+		OffchainPublicKeys: [][32]byte{{1}, {2}},
+		PeerIDs:            "peer1,peer2",
+		SharedSecretEncryptions: sharedSecretEncryptions{
+			Encryptions: [][16]byte{{1}, {2}},
+		},
+	})
+
+	params, err := models.DecodeOCRConfigEncoded(encoded, models.OCRConfigEncodedVersion)
+	require.NoError(t, err)
+	assert.Equal(t, models.OCRConfigParams{
+		DeltaProgress: 10 * time.Second,
+		DeltaResend:   5 * time.Second,
+		DeltaRound:    3 * time.Second,
+		DeltaGrace:    500 * time.Millisecond,
+		DeltaC:        time.Minute,
+		AlphaPPB:      1000000,
+		DeltaStage:    20 * time.Second,
+		RMax:          7,
+		S:             []int{1, 1, 2},
+		PeerIDs:       []string{"peer1", "peer2"},
+	}, params)
+
+	_, err = models.DecodeOCRConfigEncoded(encoded, 2)
+	assert.Error(t, err)
+
+	for _, length := range []int{0, 31, 32 * 5, len(encoded) / 2} {
+		_, err = models.DecodeOCRConfigEncoded(encoded[:length], models.OCRConfigEncodedVersion)
+		assert.Error(t, err, "truncated to %d bytes", length)
+	}
+}